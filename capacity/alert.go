@@ -0,0 +1,70 @@
+/*
+ Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package capacity
+
+import (
+	"fmt"
+)
+
+// Threshold raises an alert when a series is at least UsedPercent full, or is projected to be full
+// within DaysUntilFull days. Zero disables either check.
+type Threshold struct {
+	Severity      string
+	UsedPercent   float64
+	DaysUntilFull float64
+}
+
+// DefaultThresholds warn at 80% used or 90 days until full, and are critical at 90% or 30 days.
+var DefaultThresholds = []Threshold{
+	{Severity: "warning", UsedPercent: 80, DaysUntilFull: 90},
+	{Severity: "critical", UsedPercent: 90, DaysUntilFull: 30},
+}
+
+// Alert is a threshold crossed by a projection.
+type Alert struct {
+	Series
+	Threshold Threshold
+	Message   string
+}
+
+// soonestFull returns the earliest estimate of the projection which fills, if any.
+func soonestFull(projection *Projection) *Estimate {
+	var soonest *Estimate
+	for _, estimate := range []*Estimate{&projection.Linear, projection.Seasonal} {
+		if estimate != nil && estimate.WillFill && (soonest == nil || estimate.DaysUntilFull < soonest.DaysUntilFull) {
+			soonest = estimate
+		}
+	}
+	return soonest
+}
+
+// CheckThresholds returns an alert for each threshold the projection crosses.
+func CheckThresholds(projection *Projection, thresholds []Threshold) []Alert {
+	alerts := make([]Alert, 0)
+	soonest := soonestFull(projection)
+	for _, threshold := range thresholds {
+		var message string
+		switch {
+		case threshold.UsedPercent > 0 && projection.UsedPercent >= threshold.UsedPercent:
+			message = fmt.Sprintf("%s %s is %.1f%% used", projection.Kind, projection.ID, projection.UsedPercent)
+		case threshold.DaysUntilFull > 0 && soonest != nil && soonest.DaysUntilFull <= threshold.DaysUntilFull:
+			message = fmt.Sprintf("%s %s is projected to be full in %.1f days (%s)", projection.Kind, projection.ID, soonest.DaysUntilFull, soonest.Method)
+		default:
+			continue
+		}
+		alerts = append(alerts, Alert{Series: projection.Series, Threshold: threshold, Message: message})
+	}
+	return alerts
+}
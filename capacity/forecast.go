@@ -0,0 +1,175 @@
+/*
+ Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package capacity
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Methods of Estimate
+const (
+	MethodLinear   = "linear"
+	MethodSeasonal = "seasonal"
+)
+
+const day = 24 * time.Hour
+
+// Options control a projection.
+type Options struct {
+	// Season is the length of the repeating usage cycle, e.g. a week. Zero disables the seasonal projection.
+	Season time.Duration
+	// Buckets is the number of parts a season is divided into, e.g. 7 for the days of a week.
+	Buckets int
+	// Horizon is how far ahead the seasonal projection looks for the series to fill.
+	Horizon time.Duration
+}
+
+// DefaultOptions projects weekly seasonality, by day, up to two years ahead.
+var DefaultOptions = Options{Season: 7 * day, Buckets: 7, Horizon: 730 * day}
+
+// Estimate is when a series is projected to be full by one method.
+type Estimate struct {
+	Method   string
+	GBPerDay float64
+	// WillFill is false if the series is not projected to fill, in which case DaysUntilFull and FullAt are zero.
+	WillFill      bool
+	DaysUntilFull float64
+	FullAt        time.Time
+}
+
+// Projection is the capacity trend of a series.
+type Projection struct {
+	Series
+	Samples     int
+	From        time.Time
+	To          time.Time
+	UsedGB      float64
+	TotalGB     float64
+	UsedPercent float64
+	Linear      Estimate
+	// Seasonal is nil if the samples do not cover two seasons with a sample in every bucket.
+	Seasonal *Estimate
+}
+
+// linearFit returns the least squares intercept and slope of used GB against days since the first sample.
+func linearFit(samples []Sample) (float64, float64) {
+	n := float64(len(samples))
+	var sumX, sumY, sumXY, sumXX float64
+	for _, sample := range samples {
+		x := sample.Time.Sub(samples[0].Time).Hours() / 24
+		sumX += x
+		sumY += sample.UsedGB
+		sumXY += x * sample.UsedGB
+		sumXX += x * x
+	}
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return sumY / n, 0
+	}
+	slope := (n*sumXY - sumX*sumY) / denominator
+	return (sumY - slope*sumX) / n, slope
+}
+
+// seasonBucket returns the bucket of the season t is in, counting seasons from start.
+func seasonBucket(t, start time.Time, opts Options) int {
+	offset := t.Sub(start) % opts.Season
+	if offset < 0 {
+		offset += opts.Season
+	}
+	return int(offset / (opts.Season / time.Duration(opts.Buckets)))
+}
+
+// seasonalEstimate adds the average deviation from the linear trend in each season bucket to the trend and
+// steps forward a bucket at a time until the series fills or the horizon is reached.
+func seasonalEstimate(samples []Sample, intercept, slope, totalGB float64, opts Options) *Estimate {
+	first, last := samples[0].Time, samples[len(samples)-1].Time
+	if opts.Season <= 0 || opts.Buckets <= 0 || last.Sub(first) < 2*opts.Season {
+		return nil
+	}
+	trend := func(t time.Time) float64 {
+		return intercept + slope*t.Sub(first).Hours()/24
+	}
+	deviation := make([]float64, opts.Buckets)
+	counts := make([]int, opts.Buckets)
+	for _, sample := range samples {
+		bucket := seasonBucket(sample.Time, first, opts)
+		deviation[bucket] += sample.UsedGB - trend(sample.Time)
+		counts[bucket]++
+	}
+	for bucket := range deviation {
+		if counts[bucket] == 0 {
+			return nil
+		}
+		deviation[bucket] /= float64(counts[bucket])
+	}
+	estimate := &Estimate{Method: MethodSeasonal, GBPerDay: slope}
+	step := opts.Season / time.Duration(opts.Buckets)
+	for t := last; t.Sub(last) <= opts.Horizon; t = t.Add(step) {
+		if trend(t)+deviation[seasonBucket(t, first, opts)] >= totalGB {
+			estimate.WillFill = true
+			estimate.FullAt = t
+			estimate.DaysUntilFull = t.Sub(last).Hours() / 24
+			break
+		}
+	}
+	return estimate
+}
+
+// Project fits the samples of a series, oldest first, and estimates when it will reach the TotalGB of the
+// latest sample: by a linear trend, and by the trend plus the seasonal deviation from it.
+func Project(samples []Sample, opts Options) (*Projection, error) {
+	if len(samples) < 2 {
+		return nil, fmt.Errorf("at least two samples are needed for a projection, got %d", len(samples))
+	}
+	latest := samples[len(samples)-1]
+	if latest.TotalGB <= 0 {
+		return nil, fmt.Errorf("no total capacity for %s %s", latest.Kind, latest.ID)
+	}
+	projection := &Projection{
+		Series:      latest.Series,
+		Samples:     len(samples),
+		From:        samples[0].Time,
+		To:          latest.Time,
+		UsedGB:      latest.UsedGB,
+		TotalGB:     latest.TotalGB,
+		UsedPercent: latest.UsedGB / latest.TotalGB * 100,
+	}
+	intercept, slope := linearFit(samples)
+	projection.Linear = Estimate{Method: MethodLinear, GBPerDay: slope}
+	fitted := intercept + slope*latest.Time.Sub(samples[0].Time).Hours()/24
+	switch {
+	case latest.UsedGB >= latest.TotalGB:
+		projection.Linear.WillFill = true
+	case slope > 0:
+		projection.Linear.WillFill = true
+		projection.Linear.DaysUntilFull = math.Max(0, (latest.TotalGB-fitted)/slope)
+	}
+	if projection.Linear.WillFill {
+		projection.Linear.FullAt = latest.Time.Add(time.Duration(projection.Linear.DaysUntilFull * float64(day)))
+	}
+	projection.Seasonal = seasonalEstimate(samples, intercept, slope, latest.TotalGB, opts)
+	return projection, nil
+}
+
+// ProjectSeries projects a series from the samples in a store taken at or after since.
+func ProjectSeries(store Store, series Series, since time.Time, opts Options) (*Projection, error) {
+	samples, err := store.Samples(series, since)
+	if err != nil {
+		return nil, err
+	}
+	return Project(samples, opts)
+}
@@ -0,0 +1,123 @@
+/*
+ *
+ * Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package capacity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testSeries = Series{SymmetrixID: "000000000001", Kind: KindSRP, ID: "SRP_1"}
+
+// dailySamples returns one sample a day for days days, starting at 100GB of 1000GB, with usedGB adding to that.
+func dailySamples(days int, usedGB func(day int) float64) []Sample {
+	start := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	samples := make([]Sample, 0, days)
+	for i := 0; i < days; i++ {
+		samples = append(samples, Sample{Series: testSeries, Time: start.Add(time.Duration(i) * day), UsedGB: 100 + usedGB(i), TotalGB: 1000})
+	}
+	return samples
+}
+
+func TestProjectLinear(t *testing.T) {
+	samples := dailySamples(10, func(day int) float64 { return float64(day) * 10 })
+	projection, err := Project(samples, Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, testSeries, projection.Series)
+	assert.Equal(t, 10, projection.Samples)
+	assert.Equal(t, 190.0, projection.UsedGB)
+	assert.InDelta(t, 19.0, projection.UsedPercent, 0.001)
+	assert.Equal(t, MethodLinear, projection.Linear.Method)
+	assert.InDelta(t, 10.0, projection.Linear.GBPerDay, 0.001)
+	assert.True(t, projection.Linear.WillFill)
+	assert.InDelta(t, 81.0, projection.Linear.DaysUntilFull, 0.001)
+	assert.True(t, projection.Linear.FullAt.Equal(projection.To.Add(81*day)))
+	assert.Nil(t, projection.Seasonal)
+
+	flat := dailySamples(10, func(int) float64 { return 0 })
+	projection, err = Project(flat, Options{})
+	assert.NoError(t, err)
+	assert.False(t, projection.Linear.WillFill)
+	assert.Zero(t, projection.Linear.DaysUntilFull)
+
+	full := dailySamples(3, func(day int) float64 { return 900 + float64(day) })
+	projection, err = Project(full, Options{})
+	assert.NoError(t, err)
+	assert.True(t, projection.Linear.WillFill)
+	assert.Zero(t, projection.Linear.DaysUntilFull)
+
+	_, err = Project(samples[:1], Options{})
+	assert.Error(t, err)
+
+	samples[9].TotalGB = 0
+	_, err = Project(samples, Options{})
+	assert.Error(t, err)
+}
+
+func TestProjectSeasonal(t *testing.T) {
+	// no growth, but 950GB more is used on the fourth day of each week
+	samples := dailySamples(28, func(day int) float64 {
+		if day%7 == 3 {
+			return 950
+		}
+		return 0
+	})
+	projection, err := Project(samples, DefaultOptions)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0, projection.Linear.GBPerDay, 0.001)
+	if assert.NotNil(t, projection.Seasonal) {
+		assert.Equal(t, MethodSeasonal, projection.Seasonal.Method)
+		assert.True(t, projection.Seasonal.WillFill)
+		assert.Equal(t, 4.0, projection.Seasonal.DaysUntilFull)
+	}
+
+	// less than two seasons
+	projection, err = Project(samples[:10], DefaultOptions)
+	assert.NoError(t, err)
+	assert.Nil(t, projection.Seasonal)
+
+	store := NewMemoryStore()
+	assert.NoError(t, store.Append(samples...))
+	projection, err = ProjectSeries(store, testSeries, samples[14].Time, DefaultOptions)
+	assert.NoError(t, err)
+	assert.Equal(t, 14, projection.Samples)
+	assert.Nil(t, projection.Seasonal)
+}
+
+func TestCheckThresholds(t *testing.T) {
+	projection := &Projection{Series: testSeries, UsedPercent: 85, Linear: Estimate{Method: MethodLinear, WillFill: true, DaysUntilFull: 60}}
+	alerts := CheckThresholds(projection, DefaultThresholds)
+	assert.Len(t, alerts, 1)
+	assert.Equal(t, "warning", alerts[0].Threshold.Severity)
+	assert.Equal(t, testSeries, alerts[0].Series)
+	assert.Contains(t, alerts[0].Message, "85.0% used")
+
+	projection.UsedPercent = 50
+	projection.Seasonal = &Estimate{Method: MethodSeasonal, WillFill: true, DaysUntilFull: 20}
+	alerts = CheckThresholds(projection, DefaultThresholds)
+	assert.Len(t, alerts, 2)
+	assert.Equal(t, "critical", alerts[1].Threshold.Severity)
+	assert.Contains(t, alerts[1].Message, "20.0 days (seasonal)")
+
+	projection.Linear.WillFill = false
+	projection.Seasonal = nil
+	assert.Empty(t, CheckThresholds(projection, DefaultThresholds))
+}
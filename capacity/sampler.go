@@ -0,0 +1,187 @@
+/*
+ Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package capacity
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+	log "github.com/sirupsen/logrus"
+)
+
+// allocatedCapacityMetric is the storage group performance metric of allocated capacity in GB.
+const allocatedCapacityMetric = "AllocatedCapacity"
+
+// Source is the part of the pmax client used for sampling; pmax.Pmax satisfies it.
+type Source interface {
+	GetStoragePool(ctx context.Context, symID string, storagePoolID string) (*types.StoragePool, error)
+	GetStorageGroup(ctx context.Context, symID string, storageGroupID string) (*types.StorageGroup, error)
+	GetStorageGroupPerfKeys(ctx context.Context, symID string) (*types.StorageGroupKeysResult, error)
+	GetStorageGroupMetrics(ctx context.Context, symID string, storageGroupID string, metricsQuery []string, firstAvailableTime, lastAvailableTime int64) (*types.StorageGroupMetricsIterator, error)
+}
+
+// Sampler takes capacity samples of SRPs and storage groups of an array and appends them to a Store.
+type Sampler struct {
+	Source        Source
+	Store         Store
+	SymmetrixID   string
+	SRPs          []string
+	StorageGroups []string
+}
+
+// srpSample returns the current usable capacity of an SRP.
+func (s *Sampler) srpSample(ctx context.Context, srpID string, now time.Time) (Sample, error) {
+	srp, err := s.Source.GetStoragePool(ctx, s.SymmetrixID, srpID)
+	if err != nil {
+		return Sample{}, err
+	}
+	if srp.SrpCap == nil {
+		return Sample{}, fmt.Errorf("no capacity reported for SRP %s", srpID)
+	}
+	return Sample{
+		Series:  Series{SymmetrixID: s.SymmetrixID, Kind: KindSRP, ID: srpID},
+		Time:    now,
+		UsedGB:  srp.SrpCap.UsableUsedInTB * 1024,
+		TotalGB: srp.SrpCap.UsableTotInTB * 1024,
+	}, nil
+}
+
+// storageGroupSamples returns the allocated capacity of a storage group from its performance metrics
+// between from and to, in milliseconds since the epoch.
+func (s *Sampler) storageGroupSamples(ctx context.Context, storageGroupID string, from, to int64) ([]Sample, error) {
+	sg, err := s.Source.GetStorageGroup(ctx, s.SymmetrixID, storageGroupID)
+	if err != nil {
+		return nil, err
+	}
+	metrics, err := s.Source.GetStorageGroupMetrics(ctx, s.SymmetrixID, storageGroupID, []string{allocatedCapacityMetric}, from, to)
+	if err != nil {
+		return nil, err
+	}
+	samples := make([]Sample, 0, len(metrics.ResultList.Result))
+	for _, metric := range metrics.ResultList.Result {
+		samples = append(samples, Sample{
+			Series:  Series{SymmetrixID: s.SymmetrixID, Kind: KindStorageGroup, ID: storageGroupID},
+			Time:    time.UnixMilli(metric.Timestamp),
+			UsedGB:  metric.AllocatedCapacity,
+			TotalGB: sg.CapacityGB,
+		})
+	}
+	return samples, nil
+}
+
+// storageGroupAvailability returns the first and last available performance timestamps of the sampled storage groups.
+func (s *Sampler) storageGroupAvailability(ctx context.Context) (map[string]types.StorageGroupInfo, error) {
+	keys, err := s.Source.GetStorageGroupPerfKeys(ctx, s.SymmetrixID)
+	if err != nil {
+		return nil, err
+	}
+	available := make(map[string]types.StorageGroupInfo)
+	for _, info := range keys.StorageGroupInfos {
+		available[info.StorageGroupID] = info
+	}
+	return available, nil
+}
+
+// Sample takes one sample of each SRP, and the latest allocated capacity of each storage group, and stores them.
+// Series which cannot be sampled are skipped; the first error is returned after the other samples are stored.
+func (s *Sampler) Sample(ctx context.Context) ([]Sample, error) {
+	now := time.Now()
+	samples := make([]Sample, 0, len(s.SRPs)+len(s.StorageGroups))
+	var firstErr error
+	fail := func(series string, err error) {
+		log.Warnf("capacity sample of %s on %s failed: %s", series, s.SymmetrixID, err.Error())
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	for _, srpID := range s.SRPs {
+		sample, err := s.srpSample(ctx, srpID, now)
+		if err != nil {
+			fail(srpID, err)
+			continue
+		}
+		samples = append(samples, sample)
+	}
+	if len(s.StorageGroups) > 0 {
+		available, err := s.storageGroupAvailability(ctx)
+		if err != nil {
+			fail("storage groups", err)
+			available = make(map[string]types.StorageGroupInfo)
+		}
+		for _, storageGroupID := range s.StorageGroups {
+			info, ok := available[storageGroupID]
+			if !ok {
+				continue
+			}
+			sgSamples, err := s.storageGroupSamples(ctx, storageGroupID, info.LastAvailableDate, info.LastAvailableDate)
+			if err != nil {
+				fail(storageGroupID, err)
+				continue
+			}
+			samples = append(samples, sgSamples...)
+		}
+	}
+	if err := s.Store.Append(samples...); err != nil {
+		return nil, err
+	}
+	return samples, firstErr
+}
+
+// Backfill stores the allocated capacity history of each storage group from since until the last
+// available performance data, so that projections can be made without waiting for samples to accumulate.
+func (s *Sampler) Backfill(ctx context.Context, since time.Time) ([]Sample, error) {
+	available, err := s.storageGroupAvailability(ctx)
+	if err != nil {
+		return nil, err
+	}
+	samples := make([]Sample, 0)
+	for _, storageGroupID := range s.StorageGroups {
+		info, ok := available[storageGroupID]
+		if !ok {
+			continue
+		}
+		from := since.UnixMilli()
+		if from < info.FirstAvailableDate {
+			from = info.FirstAvailableDate
+		}
+		sgSamples, err := s.storageGroupSamples(ctx, storageGroupID, from, info.LastAvailableDate)
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, sgSamples...)
+	}
+	if err := s.Store.Append(samples...); err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
+
+// Run calls Sample every interval until ctx is done, and returns ctx.Err(). Sampling errors are logged.
+func (s *Sampler) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.Sample(ctx); err != nil {
+			log.Errorf("capacity sampling of %s failed: %s", s.SymmetrixID, err.Error())
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
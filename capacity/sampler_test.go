@@ -0,0 +1,116 @@
+/*
+ *
+ * Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package capacity
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pmax "github.com/dell/gopowermax/v2"
+	types "github.com/dell/gopowermax/v2/types/v100"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ Source = pmax.Pmax(nil)
+
+// fakeSource serves SRP_1 at 0.5TB of 2TB used, and an hourly allocated capacity history of sg_1 growing by 1GB an hour.
+type fakeSource struct {
+	start int64
+}
+
+func (f *fakeSource) GetStoragePool(_ context.Context, _ string, storagePoolID string) (*types.StoragePool, error) {
+	if storagePoolID != "SRP_1" {
+		return nil, errors.New("not found")
+	}
+	return &types.StoragePool{StoragePoolID: storagePoolID, SrpCap: &types.SrpCap{UsableUsedInTB: 0.5, UsableTotInTB: 2}}, nil
+}
+
+func (f *fakeSource) GetStorageGroup(_ context.Context, _ string, storageGroupID string) (*types.StorageGroup, error) {
+	return &types.StorageGroup{StorageGroupID: storageGroupID, CapacityGB: 100}, nil
+}
+
+func (f *fakeSource) GetStorageGroupPerfKeys(_ context.Context, _ string) (*types.StorageGroupKeysResult, error) {
+	return &types.StorageGroupKeysResult{StorageGroupInfos: []types.StorageGroupInfo{
+		{StorageGroupID: "sg_1", FirstAvailableDate: f.start, LastAvailableDate: f.start + 9*time.Hour.Milliseconds()},
+	}}, nil
+}
+
+func (f *fakeSource) GetStorageGroupMetrics(_ context.Context, _ string, _ string, metricsQuery []string, firstAvailableTime, lastAvailableTime int64) (*types.StorageGroupMetricsIterator, error) {
+	if len(metricsQuery) != 1 || metricsQuery[0] != allocatedCapacityMetric {
+		return nil, errors.New("unexpected metrics")
+	}
+	result := &types.StorageGroupMetricsIterator{}
+	for ts := firstAvailableTime; ts <= lastAvailableTime; ts += time.Hour.Milliseconds() {
+		hours := float64((ts - f.start) / time.Hour.Milliseconds())
+		result.ResultList.Result = append(result.ResultList.Result, types.StorageGroupMetric{Timestamp: ts, AllocatedCapacity: 10 + hours})
+	}
+	return result, nil
+}
+
+func TestSampler(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	sampler := &Sampler{
+		Source:        &fakeSource{start: start.UnixMilli()},
+		Store:         store,
+		SymmetrixID:   "000000000001",
+		SRPs:          []string{"SRP_1", "SRP_2"},
+		StorageGroups: []string{"sg_1", "sg_2"},
+	}
+
+	samples, err := sampler.Sample(context.Background())
+	assert.Error(t, err)
+	assert.Len(t, samples, 2)
+	assert.Equal(t, KindSRP, samples[0].Kind)
+	assert.Equal(t, 512.0, samples[0].UsedGB)
+	assert.Equal(t, 2048.0, samples[0].TotalGB)
+	assert.Equal(t, KindStorageGroup, samples[1].Kind)
+	assert.Equal(t, 19.0, samples[1].UsedGB)
+	assert.Equal(t, 100.0, samples[1].TotalGB)
+
+	// no new performance data, so the storage group point is not stored again
+	_, err = sampler.Sample(context.Background())
+	assert.Error(t, err)
+	sg := Series{SymmetrixID: "000000000001", Kind: KindStorageGroup, ID: "sg_1"}
+	stored, err := store.Samples(sg, time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, stored, 1)
+
+	store = NewMemoryStore()
+	sampler.Store = store
+	samples, err = sampler.Backfill(context.Background(), start.Add(-day))
+	assert.NoError(t, err)
+	assert.Len(t, samples, 10)
+	// sampling after a backfill does not repeat its last point
+	_, err = sampler.Sample(context.Background())
+	assert.Error(t, err)
+	stored, err = store.Samples(sg, time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, stored, 10)
+	projection, err := ProjectSeries(store, sg, time.Time{}, DefaultOptions)
+	assert.NoError(t, err)
+	assert.InDelta(t, 24.0, projection.Linear.GBPerDay, 0.001)
+	assert.InDelta(t, 81.0/24, projection.Linear.DaysUntilFull, 0.001)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, sampler.Run(ctx, time.Hour), context.Canceled)
+}
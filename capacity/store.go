@@ -0,0 +1,198 @@
+/*
+ Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package capacity samples the used capacity of SRPs and storage groups over time, stores the samples
+// and projects when they will be full.
+package capacity
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Kinds of Series
+const (
+	KindSRP          = "srp"
+	KindStorageGroup = "storage_group"
+)
+
+// Series identifies the samples of one SRP or storage group.
+type Series struct {
+	SymmetrixID string `json:"symmetrix_id"`
+	Kind        string `json:"kind"`
+	ID          string `json:"id"`
+}
+
+// Sample is the used capacity of a Series at a point in time.
+type Sample struct {
+	Series
+	Time   time.Time `json:"time"`
+	UsedGB float64   `json:"used_gb"`
+	// TotalGB is the capacity the series fills up to: the usable capacity of an SRP,
+	// or the subscribed capacity of a storage group. It is zero if unknown.
+	TotalGB float64 `json:"total_gb,omitempty"`
+}
+
+// Store keeps samples. Implementations must be safe for concurrent use.
+type Store interface {
+	// Append adds samples to the store. A sample of a series at a time the series already has a sample for
+	// is dropped, so that sampling the same performance data twice does not bias projections.
+	Append(samples ...Sample) error
+	// Samples returns the samples of a series taken at or after since, oldest first.
+	Samples(series Series, since time.Time) ([]Sample, error)
+}
+
+// sampleKey identifies the sample of a series at a point in time. Stores keep one sample per key.
+type sampleKey struct {
+	Series
+	nanos int64
+}
+
+// newSamples returns the samples whose series and time are not in seen, adding them to seen.
+func newSamples(seen map[sampleKey]bool, samples []Sample) []Sample {
+	result := make([]Sample, 0, len(samples))
+	for _, sample := range samples {
+		key := sampleKey{Series: sample.Series, nanos: sample.Time.UnixNano()}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, sample)
+	}
+	return result
+}
+
+// filterSamples returns the samples of a series taken at or after since, oldest first.
+func filterSamples(samples []Sample, series Series, since time.Time) []Sample {
+	result := make([]Sample, 0)
+	for _, sample := range samples {
+		if sample.Series == series && !sample.Time.Before(since) {
+			result = append(result, sample)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Time.Before(result[j].Time) })
+	return result
+}
+
+// MemoryStore is a Store which keeps samples in memory.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	samples []Sample
+	seen    map[sampleKey]bool
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{samples: make([]Sample, 0), seen: make(map[sampleKey]bool)}
+}
+
+// Append adds samples to the store, dropping those of a series and time already stored.
+func (s *MemoryStore) Append(samples ...Sample) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.samples = append(s.samples, newSamples(s.seen, samples)...)
+	return nil
+}
+
+// Samples returns the samples of a series taken at or after since, oldest first.
+func (s *MemoryStore) Samples(series Series, since time.Time) ([]Sample, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return filterSamples(s.samples, series, since), nil
+}
+
+// FileStore is a Store which appends samples to a local file, one JSON object per line.
+type FileStore struct {
+	mu   sync.Mutex
+	path string
+	// seen holds the series and times in the file; it is read when samples are first appended.
+	seen map[sampleKey]bool
+}
+
+// NewFileStore returns a FileStore using the file at path, which is created when samples are first appended.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Append adds samples to the end of the file, dropping those of a series and time already in the file.
+func (s *FileStore) Append(samples ...Sample) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.seen == nil {
+		stored, err := s.readSamples()
+		if err != nil {
+			return err
+		}
+		s.seen = make(map[sampleKey]bool)
+		newSamples(s.seen, stored)
+	}
+	samples = newSamples(s.seen, samples)
+	if len(samples) == 0 {
+		return nil
+	}
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, sample := range samples {
+		if err = encoder.Encode(sample); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	if err = writer.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Samples reads the file and returns the samples of a series taken at or after since, oldest first.
+func (s *FileStore) Samples(series Series, since time.Time) ([]Sample, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	samples, err := s.readSamples()
+	if err != nil {
+		return nil, err
+	}
+	return filterSamples(samples, series, since), nil
+}
+
+// readSamples returns all the samples in the file. The caller holds s.mu.
+func (s *FileStore) readSamples() ([]Sample, error) {
+	file, err := os.Open(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return make([]Sample, 0), nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+	samples := make([]Sample, 0)
+	decoder := json.NewDecoder(bufio.NewReader(file))
+	for decoder.More() {
+		sample := Sample{}
+		if err = decoder.Decode(&sample); err != nil {
+			return nil, err
+		}
+		samples = append(samples, sample)
+	}
+	return samples, nil
+}
@@ -0,0 +1,82 @@
+/*
+ *
+ * Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package capacity
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testStore(t *testing.T, store Store) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	srp := Series{SymmetrixID: "000000000001", Kind: KindSRP, ID: "SRP_1"}
+	sg := Series{SymmetrixID: "000000000001", Kind: KindStorageGroup, ID: "sg_1"}
+
+	samples, err := store.Samples(srp, start)
+	assert.NoError(t, err)
+	assert.Empty(t, samples)
+
+	assert.NoError(t, store.Append(
+		Sample{Series: srp, Time: start.Add(2 * time.Hour), UsedGB: 20, TotalGB: 100},
+		Sample{Series: sg, Time: start, UsedGB: 5},
+		Sample{Series: srp, Time: start, UsedGB: 10, TotalGB: 100},
+	))
+	assert.NoError(t, store.Append(Sample{Series: srp, Time: start.Add(time.Hour), UsedGB: 15, TotalGB: 100}))
+	// a series already sampled at a time is not sampled again
+	assert.NoError(t, store.Append(Sample{Series: srp, Time: start, UsedGB: 10, TotalGB: 100}))
+
+	samples, err = store.Samples(srp, start)
+	assert.NoError(t, err)
+	assert.Len(t, samples, 3)
+	assert.Equal(t, 10.0, samples[0].UsedGB)
+	assert.Equal(t, 20.0, samples[2].UsedGB)
+	assert.True(t, samples[2].Time.Equal(start.Add(2*time.Hour)))
+
+	samples, err = store.Samples(srp, start.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, samples, 2)
+
+	samples, err = store.Samples(sg, start)
+	assert.NoError(t, err)
+	assert.Len(t, samples, 1)
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+}
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "samples.json")
+	testStore(t, NewFileStore(path))
+
+	// a new store on the same file sees the samples
+	samples, err := NewFileStore(path).Samples(Series{SymmetrixID: "000000000001", Kind: KindSRP, ID: "SRP_1"}, time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, samples, 3)
+
+	// nor does it append samples already in the file
+	store := NewFileStore(path)
+	assert.NoError(t, store.Append(Sample{Series: Series{SymmetrixID: "000000000001", Kind: KindSRP, ID: "SRP_1"}, Time: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), UsedGB: 10}))
+	samples, err = store.Samples(Series{SymmetrixID: "000000000001", Kind: KindSRP, ID: "SRP_1"}, time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, samples, 3)
+}
@@ -0,0 +1,347 @@
+/*
+ Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pmax
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+	log "github.com/sirupsen/logrus"
+)
+
+// Kinds of CapacityReportRow
+const (
+	CapacityByStorageGroup = "storage_group"
+	CapacityBySRP          = "srp"
+	CapacityByServiceLevel = "service_level"
+	CapacityByTag          = "tag"
+)
+
+// capacityReportColumns is the CSV header of a CapacityReport.
+var capacityReportColumns = []string{
+	"kind", "name", "storage_groups", "subscribed_gb", "allocated_gb", "effective_gb", "unreducible_data_gb",
+	"compression_ratio_to_one", "vp_saved_percent", "usable_total_gb", "usable_used_gb", "headroom_gb", "subscription_percent",
+}
+
+// CapacityReportRow is the capacity of a storage group, or of the storage groups of an SRP, service level or tag.
+type CapacityReportRow struct {
+	Kind          string `json:"kind"`
+	Name          string `json:"name"`
+	StorageGroups int    `json:"storage_groups"`
+	// SubscribedGB is the provisioned capacity of the volumes.
+	SubscribedGB float64 `json:"subscribed_gb"`
+	// AllocatedGB is the capacity written by hosts, before data reduction.
+	AllocatedGB float64 `json:"allocated_gb"`
+	// EffectiveGB estimates the capacity used after data reduction, from AllocatedGB and CompressionRatioToOne.
+	EffectiveGB       float64 `json:"effective_gb"`
+	UnreducibleDataGB float64 `json:"unreducible_data_gb"`
+	// CompressionRatioToOne and VPSavedPercent are weighted by subscribed capacity in rolled up rows.
+	CompressionRatioToOne float64 `json:"compression_ratio_to_one"`
+	VPSavedPercent        float64 `json:"vp_saved_percent"`
+	// The SRP limits are only set in CapacityBySRP rows.
+	UsableTotalGB float64 `json:"usable_total_gb,omitempty"`
+	UsableUsedGB  float64 `json:"usable_used_gb,omitempty"`
+	// HeadroomGB is the usable capacity of the SRP which is still free.
+	HeadroomGB float64 `json:"headroom_gb,omitempty"`
+	// SubscriptionPercent is the subscribed capacity of the SRP as a percentage of its usable capacity.
+	SubscriptionPercent float64 `json:"subscription_percent,omitempty"`
+
+	// weights of the ratios, for rolling up
+	compressionWeight float64
+	vpSavedWeight     float64
+}
+
+// CapacityReport rolls up storage group capacity and data reduction per storage group, SRP, service level and tag.
+// Parent storage groups are reported but not rolled up, as their capacity is that of their children.
+type CapacityReport struct {
+	SymmetrixID string    `json:"symmetrix_id"`
+	Generated   time.Time `json:"generated"`
+	// VolumeCapacity is false if volume allocations could not be read, in which case AllocatedGB and EffectiveGB are zero.
+	VolumeCapacity bool                `json:"volume_capacity"`
+	Rows           []CapacityReportRow `json:"rows"`
+	// Errors maps storage groups or SRPs which could not be read to the error.
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// add rolls the figures of a storage group row into r. It is used when volume capacity is not available, in
+// which case a volume in several storage groups is counted once for each of them.
+func (r *CapacityReportRow) add(sg *CapacityReportRow) {
+	r.StorageGroups++
+	r.SubscribedGB += sg.SubscribedGB
+	r.AllocatedGB += sg.AllocatedGB
+	r.EffectiveGB += sg.EffectiveGB
+	r.UnreducibleDataGB += sg.UnreducibleDataGB
+	r.addRatios(sg.CompressionRatioToOne, sg.VPSavedPercent, sg.SubscribedGB)
+}
+
+// addVolume rolls the figures of a volume of storage group sg into r. The data reduction figures of sg are
+// applied to the volume in proportion to its subscribed capacity.
+func (r *CapacityReportRow) addVolume(volume *types.VolumeEnhanced, sg *types.StorageGroup) {
+	r.SubscribedGB += volume.CapGB
+	r.AllocatedGB += volume.EffectiveUsedCapacityGB
+	if sg.CompressionRatioToOne > 1 {
+		r.EffectiveGB += volume.EffectiveUsedCapacityGB / sg.CompressionRatioToOne
+	} else {
+		r.EffectiveGB += volume.EffectiveUsedCapacityGB
+	}
+	if sg.CapacityGB > 0 {
+		r.UnreducibleDataGB += sg.UnreducibleDataGB * volume.CapGB / sg.CapacityGB
+	}
+	r.addRatios(sg.CompressionRatioToOne, sg.VPSavedPercent, volume.CapGB)
+}
+
+// addRatios adds the data reduction ratios of weightGB of subscribed capacity to the weighted sums of r.
+func (r *CapacityReportRow) addRatios(compressionRatioToOne, vpSavedPercent, weightGB float64) {
+	if compressionRatioToOne > 0 {
+		r.compressionWeight += weightGB
+		r.CompressionRatioToOne += compressionRatioToOne * weightGB
+	}
+	r.vpSavedWeight += weightGB
+	r.VPSavedPercent += vpSavedPercent * weightGB
+}
+
+// finish turns the weighted sums of the ratios into averages.
+func (r *CapacityReportRow) finish() {
+	if r.compressionWeight > 0 {
+		r.CompressionRatioToOne /= r.compressionWeight
+	} else {
+		r.CompressionRatioToOne = 0
+	}
+	if r.vpSavedWeight > 0 {
+		r.VPSavedPercent /= r.vpSavedWeight
+	} else {
+		r.VPSavedPercent = 0
+	}
+}
+
+// getStorageGroupAllocations returns the capacity used by the volumes of each storage group of an array.
+func (c *Client) getStorageGroupAllocations(ctx context.Context, symID string) (map[string]float64, error) {
+	volumes, err := c.GetVolumesCapacityBulk(ctx, symID)
+	if err != nil {
+		return nil, err
+	}
+	return storageGroupAllocations(volumes.Volumes), nil
+}
+
+// storageGroupAllocations sums the capacity used by volumes per storage group.
+func storageGroupAllocations(volumes []types.VolumeEnhanced) map[string]float64 {
+	allocated := make(map[string]float64)
+	for _, volume := range volumes {
+		for _, sg := range volume.StorageGroups {
+			allocated[sg.StorageGroupID] += volume.EffectiveUsedCapacityGB
+		}
+	}
+	return allocated
+}
+
+// storageGroupCapacityRow returns the row of a storage group with allocatedGB written to its volumes.
+func storageGroupCapacityRow(sg *types.StorageGroup, allocatedGB float64) CapacityReportRow {
+	row := CapacityReportRow{
+		Kind:                  CapacityByStorageGroup,
+		Name:                  sg.StorageGroupID,
+		StorageGroups:         1,
+		SubscribedGB:          sg.CapacityGB,
+		AllocatedGB:           allocatedGB,
+		EffectiveGB:           allocatedGB,
+		UnreducibleDataGB:     sg.UnreducibleDataGB,
+		CompressionRatioToOne: sg.CompressionRatioToOne,
+		VPSavedPercent:        sg.VPSavedPercent,
+	}
+	if sg.CompressionRatioToOne > 1 {
+		row.EffectiveGB = allocatedGB / sg.CompressionRatioToOne
+	}
+	return row
+}
+
+// storageGroupServiceLevel returns the service level of a storage group, or "None".
+func storageGroupServiceLevel(sg *types.StorageGroup) string {
+	for _, serviceLevel := range []string{sg.SLO, sg.ServiceLevel} {
+		if serviceLevel != "" {
+			return serviceLevel
+		}
+	}
+	return "None"
+}
+
+// capacityRollupKeys returns the SRP, service level and tag rollups of a storage group, as kind/name keys.
+func capacityRollupKeys(sg *types.StorageGroup) []string {
+	keys := make([]string, 0, 4)
+	if sg.SRP != "" && sg.SRP != "None" {
+		keys = append(keys, CapacityBySRP+"/"+sg.SRP)
+	}
+	keys = append(keys, CapacityByServiceLevel+"/"+storageGroupServiceLevel(sg))
+	for _, tag := range strings.Split(sg.Tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			keys = append(keys, CapacityByTag+"/"+tag)
+		}
+	}
+	return keys
+}
+
+// GetCapacityReport returns the capacity report of all the storage groups of an array. Volume allocations are
+// read in bulk with GetVolumesCapacityBulk and the SRP, service level and tag rows are summed from them, so a
+// volume in several storage groups is counted once. If the volumes cannot be read the report is still produced,
+// rolling up the storage group rows instead.
+func (c *Client) GetCapacityReport(ctx context.Context, symID string) (*CapacityReport, error) {
+	defer c.TimeSpent("GetCapacityReport", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	sgIDList, err := c.GetStorageGroupIDList(ctx, symID, "", false)
+	if err != nil {
+		return nil, err
+	}
+	report := &CapacityReport{
+		SymmetrixID: symID,
+		Generated:   time.Now(),
+		Rows:        make([]CapacityReportRow, 0),
+		Errors:      make(map[string]string),
+	}
+
+	var mu sync.Mutex
+	storageGroups := make([]*types.StorageGroup, 0, len(sgIDList.StorageGroupIDs))
+	if err := runBounded(ctx, DefaultRequestConcurrency, len(sgIDList.StorageGroupIDs), func(i int) {
+		sg, err := c.GetStorageGroup(ctx, symID, sgIDList.StorageGroupIDs[i])
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			report.Errors[sgIDList.StorageGroupIDs[i]] = err.Error()
+			return
+		}
+		storageGroups = append(storageGroups, sg)
+	}); err != nil {
+		return nil, err
+	}
+	sort.Slice(storageGroups, func(i, j int) bool { return storageGroups[i].StorageGroupID < storageGroups[j].StorageGroupID })
+
+	var volumes []types.VolumeEnhanced
+	if volumeList, err := c.GetVolumesCapacityBulk(ctx, symID); err != nil {
+		log.Warnf("GetCapacityReport: no volume allocations for %s: %s", symID, err.Error())
+	} else {
+		volumes = volumeList.Volumes
+		report.VolumeCapacity = true
+	}
+	allocated := storageGroupAllocations(volumes)
+
+	rollups := make(map[string]*CapacityReportRow)
+	rollupRow := func(key string) *CapacityReportRow {
+		if rollups[key] == nil {
+			kind, name, _ := strings.Cut(key, "/")
+			rollups[key] = &CapacityReportRow{Kind: kind, Name: name}
+		}
+		return rollups[key]
+	}
+	rolledUp := make(map[string]*types.StorageGroup)
+	for _, sg := range storageGroups {
+		row := storageGroupCapacityRow(sg, allocated[sg.StorageGroupID])
+		report.Rows = append(report.Rows, row)
+		if sg.NumOfChildSGs > 0 {
+			continue
+		}
+		rolledUp[sg.StorageGroupID] = sg
+		for _, key := range capacityRollupKeys(sg) {
+			if report.VolumeCapacity {
+				rollupRow(key).StorageGroups++
+			} else {
+				rollupRow(key).add(&row)
+			}
+		}
+	}
+	// a volume may be in several storage groups of the same rollup, so the rollups are built from the
+	// volumes, counting each volume once per rollup
+	counted := make(map[string]map[string]bool)
+	for i := range volumes {
+		volume := &volumes[i]
+		for _, volumeSG := range volume.StorageGroups {
+			sg := rolledUp[volumeSG.StorageGroupID]
+			if sg == nil {
+				continue
+			}
+			for _, key := range capacityRollupKeys(sg) {
+				if counted[key] == nil {
+					counted[key] = make(map[string]bool)
+				}
+				if counted[key][volume.ID] {
+					continue
+				}
+				counted[key][volume.ID] = true
+				rollupRow(key).addVolume(volume, sg)
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(rollups))
+	for key := range rollups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		row := rollups[key]
+		row.finish()
+		if row.Kind == CapacityBySRP {
+			srp, err := c.GetStoragePool(ctx, symID, row.Name)
+			if err != nil {
+				report.Errors[row.Name] = err.Error()
+			} else if srp.SrpCap != nil {
+				row.UsableTotalGB = srp.SrpCap.UsableTotInTB * 1024
+				row.UsableUsedGB = srp.SrpCap.UsableUsedInTB * 1024
+				row.HeadroomGB = row.UsableTotalGB - row.UsableUsedGB
+				if row.UsableTotalGB > 0 {
+					row.SubscriptionPercent = srp.SrpCap.SubTotInTB * 1024 / row.UsableTotalGB * 100
+				}
+			}
+		}
+		report.Rows = append(report.Rows, *row)
+	}
+	return report, nil
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *CapacityReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteCSV writes the rows of the report as CSV with a header line.
+func (r *CapacityReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(capacityReportColumns); err != nil {
+		return err
+	}
+	format := func(f float64) string {
+		return strconv.FormatFloat(f, 'f', 2, 64)
+	}
+	for _, row := range r.Rows {
+		record := []string{
+			row.Kind, row.Name, strconv.Itoa(row.StorageGroups), format(row.SubscribedGB), format(row.AllocatedGB),
+			format(row.EffectiveGB), format(row.UnreducibleDataGB), format(row.CompressionRatioToOne), format(row.VPSavedPercent),
+			format(row.UsableTotalGB), format(row.UsableUsedGB), format(row.HeadroomGB), format(row.SubscriptionPercent),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,204 @@
+/*
+ *
+ * Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pmax
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+	"github.com/stretchr/testify/assert"
+)
+
+// newCapacityReportServer serves three storage groups in SRP_1: sg_1 (Diamond, tags a and b, 2:1 compression),
+// sg_2 (no service level, tag b, no compression) and their parent sg_p. If volumesFail is set the bulk
+// volume query fails.
+func newCapacityReportServer(volumesFail bool) *httptest.Server {
+	storageGroups := map[string]*types.StorageGroup{
+		"sg_1": {StorageGroupID: "sg_1", SLO: "Diamond", SRP: "SRP_1", CapacityGB: 100, CompressionRatioToOne: 2, VPSavedPercent: 50, Tags: "a, b", UnreducibleDataGB: 5},
+		"sg_2": {StorageGroupID: "sg_2", SRP: "SRP_1", CapacityGB: 300, VPSavedPercent: 10, Tags: "b"},
+		"sg_p": {StorageGroupID: "sg_p", SLO: "Diamond", SRP: "SRP_1", CapacityGB: 400, NumOfChildSGs: 2},
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := r.URL.Path
+		last := path[strings.LastIndex(path, "/")+1:]
+		var out interface{}
+		switch {
+		case strings.HasSuffix(path, XStorageGroup):
+			out = &types.StorageGroupIDList{StorageGroupIDs: []string{"sg_p", "sg_2", "sg_1"}}
+		case strings.Contains(path, XStorageGroup+"/") && storageGroups[last] != nil:
+			out = storageGroups[last]
+		case strings.HasSuffix(path, XVolumeV1) && !volumesFail:
+			out = &types.Volumev1{Volumes: []types.VolumeEnhanced{
+				{ID: "00001", CapGB: 100, EffectiveUsedCapacityGB: 40, StorageGroups: []types.StorageGroupID{{StorageGroupID: "sg_1"}, {StorageGroupID: "sg_p"}}},
+				{ID: "00002", CapGB: 300, EffectiveUsedCapacityGB: 60, StorageGroups: []types.StorageGroupID{{StorageGroupID: "sg_2"}, {StorageGroupID: "sg_p"}}},
+			}}
+		case strings.HasSuffix(path, "/"+StorageResourcePool+"/SRP_1"):
+			out = &types.StoragePool{StoragePoolID: "SRP_1", SrpCap: &types.SrpCap{SubTotInTB: 0.5, UsableTotInTB: 1, UsableUsedInTB: 0.25}}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"not found"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(out)
+	}))
+}
+
+func findCapacityRow(report *CapacityReport, kind, name string) *CapacityReportRow {
+	for i := range report.Rows {
+		if report.Rows[i].Kind == kind && report.Rows[i].Name == name {
+			return &report.Rows[i]
+		}
+	}
+	return nil
+}
+
+func TestGetCapacityReport(t *testing.T) {
+	symID := "000000000001"
+	server := newCapacityReportServer(false)
+	defer server.Close()
+	c, err := NewClientWithArgs(server.URL, "", true, true, "")
+	assert.NoError(t, err)
+	c.SetAllowedArrays([]string{symID})
+
+	report, err := c.GetCapacityReport(context.Background(), symID)
+	assert.NoError(t, err)
+	assert.True(t, report.VolumeCapacity)
+	assert.Empty(t, report.Errors)
+	assert.Len(t, report.Rows, 8)
+	assert.Equal(t, "sg_1", report.Rows[0].Name)
+
+	sg1 := findCapacityRow(report, CapacityByStorageGroup, "sg_1")
+	assert.Equal(t, 40.0, sg1.AllocatedGB)
+	assert.Equal(t, 20.0, sg1.EffectiveGB)
+	parent := findCapacityRow(report, CapacityByStorageGroup, "sg_p")
+	assert.Equal(t, 100.0, parent.AllocatedGB)
+
+	// the parent is not rolled up
+	srp := findCapacityRow(report, CapacityBySRP, "SRP_1")
+	assert.Equal(t, 2, srp.StorageGroups)
+	assert.Equal(t, 400.0, srp.SubscribedGB)
+	assert.Equal(t, 100.0, srp.AllocatedGB)
+	assert.Equal(t, 80.0, srp.EffectiveGB)
+	assert.Equal(t, 2.0, srp.CompressionRatioToOne)
+	assert.Equal(t, 20.0, srp.VPSavedPercent)
+	assert.Equal(t, 1024.0, srp.UsableTotalGB)
+	assert.Equal(t, 768.0, srp.HeadroomGB)
+	assert.Equal(t, 50.0, srp.SubscriptionPercent)
+
+	diamond := findCapacityRow(report, CapacityByServiceLevel, "Diamond")
+	assert.Equal(t, 1, diamond.StorageGroups)
+	assert.Equal(t, 1, findCapacityRow(report, CapacityByServiceLevel, "None").StorageGroups)
+	assert.Equal(t, 1, findCapacityRow(report, CapacityByTag, "a").StorageGroups)
+	assert.Equal(t, 400.0, findCapacityRow(report, CapacityByTag, "b").SubscribedGB)
+
+	var buf bytes.Buffer
+	assert.NoError(t, report.WriteCSV(&buf))
+	records, err := csv.NewReader(&buf).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, records, 9)
+	assert.Equal(t, capacityReportColumns, records[0])
+	assert.Equal(t, []string{"storage_group", "sg_1", "1", "100.00", "40.00", "20.00"}, records[1][:6])
+
+	buf.Reset()
+	assert.NoError(t, report.WriteJSON(&buf))
+	decoded := &CapacityReport{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), decoded))
+	assert.Equal(t, report.Rows[0].SubscribedGB, decoded.Rows[0].SubscribedGB)
+	assert.Equal(t, symID, decoded.SymmetrixID)
+
+	_, err = c.GetCapacityReport(context.Background(), "000000000002")
+	assert.Error(t, err)
+}
+
+func TestGetCapacityReportWithoutVolumes(t *testing.T) {
+	symID := "000000000001"
+	server := newCapacityReportServer(true)
+	defer server.Close()
+	c, err := NewClientWithArgs(server.URL, "", true, true, "")
+	assert.NoError(t, err)
+	c.SetAllowedArrays([]string{symID})
+
+	report, err := c.GetCapacityReport(context.Background(), symID)
+	assert.NoError(t, err)
+	assert.False(t, report.VolumeCapacity)
+	srp := findCapacityRow(report, CapacityBySRP, "SRP_1")
+	assert.Equal(t, 400.0, srp.SubscribedGB)
+	assert.Equal(t, 0.0, srp.AllocatedGB)
+}
+
+func TestGetCapacityReportSharedVolume(t *testing.T) {
+	symID := "000000000001"
+	// volume 00001 is in both sg_a and sg_b, which are not cascaded
+	storageGroups := map[string]*types.StorageGroup{
+		"sg_a": {StorageGroupID: "sg_a", SLO: "Diamond", SRP: "SRP_1", CapacityGB: 100, Tags: "x"},
+		"sg_b": {StorageGroupID: "sg_b", SLO: "Diamond", SRP: "SRP_1", CapacityGB: 150, Tags: "x"},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := r.URL.Path
+		last := path[strings.LastIndex(path, "/")+1:]
+		var out interface{}
+		switch {
+		case strings.HasSuffix(path, XStorageGroup):
+			out = &types.StorageGroupIDList{StorageGroupIDs: []string{"sg_a", "sg_b"}}
+		case strings.Contains(path, XStorageGroup+"/") && storageGroups[last] != nil:
+			out = storageGroups[last]
+		case strings.HasSuffix(path, XVolumeV1):
+			out = &types.Volumev1{Volumes: []types.VolumeEnhanced{
+				{ID: "00001", CapGB: 100, EffectiveUsedCapacityGB: 40, StorageGroups: []types.StorageGroupID{{StorageGroupID: "sg_a"}, {StorageGroupID: "sg_b"}}},
+				{ID: "00002", CapGB: 50, EffectiveUsedCapacityGB: 10, StorageGroups: []types.StorageGroupID{{StorageGroupID: "sg_b"}}},
+			}}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"not found"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(out)
+	}))
+	defer server.Close()
+	c, err := NewClientWithArgs(server.URL, "", true, true, "")
+	assert.NoError(t, err)
+	c.SetAllowedArrays([]string{symID})
+
+	report, err := c.GetCapacityReport(context.Background(), symID)
+	assert.NoError(t, err)
+	assert.True(t, report.VolumeCapacity)
+	assert.Equal(t, 40.0, findCapacityRow(report, CapacityByStorageGroup, "sg_a").AllocatedGB)
+	assert.Equal(t, 50.0, findCapacityRow(report, CapacityByStorageGroup, "sg_b").AllocatedGB)
+	for _, row := range []*CapacityReportRow{
+		findCapacityRow(report, CapacityBySRP, "SRP_1"),
+		findCapacityRow(report, CapacityByServiceLevel, "Diamond"),
+		findCapacityRow(report, CapacityByTag, "x"),
+	} {
+		assert.Equal(t, 2, row.StorageGroups)
+		assert.Equal(t, 150.0, row.SubscribedGB)
+		assert.Equal(t, 50.0, row.AllocatedGB)
+		assert.Equal(t, 50.0, row.EffectiveGB)
+	}
+	// the SRP capacity could not be read
+	assert.Contains(t, report.Errors, "SRP_1")
+}
@@ -0,0 +1,53 @@
+/*
+ Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pmax
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultRequestConcurrency is the number of concurrent requests made by the operations which fan out over
+// many objects, such as DiscoverTargets, GetCapacityReport and StreamVolumes, when no concurrency is given.
+var DefaultRequestConcurrency = 8
+
+// runBounded calls fn for every index in [0, n) using at most limit goroutines and waits for the calls it started.
+// Once ctx is done no further calls are started and the error of ctx is returned.
+func runBounded(ctx context.Context, limit, n int, fn func(i int)) error {
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for i := 0; i < n; i++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case sem <- struct{}{}:
+		}
+		// both cases may have been ready
+		if err := ctx.Err(); err != nil {
+			<-sem
+			return err
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(i)
+		}(i)
+	}
+	return nil
+}
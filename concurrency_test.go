@@ -0,0 +1,46 @@
+/*
+ *
+ * Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pmax
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunBounded(t *testing.T) {
+	var calls atomic.Int32
+	assert.NoError(t, runBounded(context.Background(), 2, 10, func(int) { calls.Add(1) }))
+	assert.Equal(t, int32(10), calls.Load())
+
+	// no more calls are started once ctx is done
+	calls.Store(0)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err := runBounded(ctx, 1, 10, func(i int) {
+		calls.Add(1)
+		if i == 2 {
+			cancel()
+		}
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, int32(3), calls.Load())
+}
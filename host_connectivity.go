@@ -0,0 +1,176 @@
+/*
+ Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pmax
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+)
+
+// DefaultMinHostPaths is the number of paths below which GetHostConnectivityReport flags a host when no minimum is given.
+var DefaultMinHostPaths = 2
+
+// HostInitiatorConnectivity is the connectivity of one host initiator (HBA or IQN).
+type HostInitiatorConnectivity struct {
+	// Initiator is the WWN or IQN as listed in the host.
+	Initiator string
+	// InitiatorIDs are the array initiator records (director:port:initiator) of the initiator.
+	InitiatorIDs []string
+	// LoggedInPorts are the array ports the initiator is logged into.
+	LoggedInPorts []types.PortKey
+	// MissingPorts are the port group ports the initiator should reach but is not logged into.
+	MissingPorts []types.PortKey
+	// OutsidePortGroup are the ports the initiator is logged into which are in none of the host's port groups.
+	OutsidePortGroup []types.PortKey
+	// Paths is the number of port group ports the initiator is logged into.
+	Paths int
+}
+
+// HostConnectivityReport describes the paths between a host and the ports of its masking views.
+type HostConnectivityReport struct {
+	SymmetrixID    string
+	HostID         string
+	MaskingViewIDs []string
+	PortGroupIDs   []string
+	// ExpectedPorts are the ports of all the host's port groups.
+	ExpectedPorts []types.PortKey
+	Initiators    []HostInitiatorConnectivity
+	// Paths is the total number of logged in initiator to port group port paths.
+	Paths    int
+	MinPaths int
+	// InsufficientPaths is set if Paths is less than MinPaths.
+	InsufficientPaths bool
+}
+
+// sortPortKeys sorts port keys by director and port.
+func sortPortKeys(keys []types.PortKey) {
+	sort.Slice(keys, func(i, j int) bool {
+		return portKeyString(keys[i].DirectorID, keys[i].PortID) < portKeyString(keys[j].DirectorID, keys[j].PortID)
+	})
+}
+
+// initiatorIDMatches reports whether an array initiator ID (director:port:initiator, or just initiator) is of the given initiator.
+func initiatorIDMatches(initiatorID, initiator string) bool {
+	initiatorID = strings.ToLower(initiatorID)
+	initiator = strings.ToLower(initiator)
+	return initiatorID == initiator || strings.HasSuffix(initiatorID, ":"+initiator)
+}
+
+// GetHostConnectivityReport returns, for each initiator of a host, the array ports it is logged into and the ports
+// of the host's port groups it should reach but does not. The host is flagged if it has fewer than minPaths
+// logged in paths to its port group ports; DefaultMinHostPaths is used if minPaths is not positive.
+func (c *Client) GetHostConnectivityReport(ctx context.Context, symID string, hostID string, minPaths int) (*HostConnectivityReport, error) {
+	defer c.TimeSpent("GetHostConnectivityReport", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	if minPaths <= 0 {
+		minPaths = DefaultMinHostPaths
+	}
+	host, err := c.GetHostByID(ctx, symID, hostID)
+	if err != nil {
+		return nil, err
+	}
+	report := &HostConnectivityReport{
+		SymmetrixID:    symID,
+		HostID:         hostID,
+		MaskingViewIDs: host.MaskingviewIDs,
+		PortGroupIDs:   make([]string, 0),
+		ExpectedPorts:  make([]types.PortKey, 0),
+		Initiators:     make([]HostInitiatorConnectivity, 0),
+		MinPaths:       minPaths,
+	}
+
+	expected := make(map[string]bool)
+	for _, maskingViewID := range host.MaskingviewIDs {
+		maskingView, err := c.GetMaskingViewByID(ctx, symID, maskingViewID)
+		if err != nil {
+			return nil, err
+		}
+		if maskingView.PortGroupID == "" || stringInSlice(maskingView.PortGroupID, report.PortGroupIDs) {
+			continue
+		}
+		portGroup, err := c.GetPortGroupByID(ctx, symID, maskingView.PortGroupID)
+		if err != nil {
+			return nil, err
+		}
+		report.PortGroupIDs = append(report.PortGroupIDs, maskingView.PortGroupID)
+		for _, key := range portGroup.SymmetrixPortKey {
+			name := portKeyString(key.DirectorID, key.PortID)
+			if !expected[name] {
+				expected[name] = true
+				report.ExpectedPorts = append(report.ExpectedPorts, key)
+			}
+		}
+	}
+	sortPortKeys(report.ExpectedPorts)
+
+	for _, initiator := range host.Initiators {
+		connectivity := HostInitiatorConnectivity{
+			Initiator:        initiator,
+			InitiatorIDs:     make([]string, 0),
+			LoggedInPorts:    make([]types.PortKey, 0),
+			MissingPorts:     make([]types.PortKey, 0),
+			OutsidePortGroup: make([]types.PortKey, 0),
+		}
+		initList, err := c.GetInitiatorList(ctx, symID, initiator, false, false)
+		if err != nil {
+			return nil, err
+		}
+		loggedIn := make(map[string]bool)
+		for _, initiatorID := range initList.InitiatorIDs {
+			if !initiatorIDMatches(initiatorID, initiator) {
+				continue
+			}
+			connectivity.InitiatorIDs = append(connectivity.InitiatorIDs, initiatorID)
+			record, err := c.GetInitiatorByID(ctx, symID, initiatorID)
+			if err != nil {
+				return nil, err
+			}
+			if !record.LoggedIn {
+				continue
+			}
+			for _, key := range record.SymmetrixPortKey {
+				name := portKeyString(key.DirectorID, key.PortID)
+				if loggedIn[name] {
+					continue
+				}
+				loggedIn[name] = true
+				connectivity.LoggedInPorts = append(connectivity.LoggedInPorts, key)
+				if expected[name] {
+					connectivity.Paths++
+				} else {
+					connectivity.OutsidePortGroup = append(connectivity.OutsidePortGroup, key)
+				}
+			}
+		}
+		for _, key := range report.ExpectedPorts {
+			if !loggedIn[portKeyString(key.DirectorID, key.PortID)] {
+				connectivity.MissingPorts = append(connectivity.MissingPorts, key)
+			}
+		}
+		sort.Strings(connectivity.InitiatorIDs)
+		sortPortKeys(connectivity.LoggedInPorts)
+		sortPortKeys(connectivity.OutsidePortGroup)
+		report.Paths += connectivity.Paths
+		report.Initiators = append(report.Initiators, connectivity)
+	}
+	report.InsufficientPaths = report.Paths < minPaths
+	return report, nil
+}
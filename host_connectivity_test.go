@@ -0,0 +1,110 @@
+/*
+ *
+ * Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pmax
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+	"github.com/stretchr/testify/assert"
+)
+
+// newHostConnectivityServer serves host_1 with two HBAs in two masking views sharing port group pg_1
+// (FA-1D:4 and FA-2D:4). HBA a is logged into FA-1D:4 and FA-3D:1; HBA b is not logged in.
+// The initiator list ignores its filter, as older Unisphere versions do.
+func newHostConnectivityServer() *httptest.Server {
+	initiators := map[string]*types.Initiator{
+		"FA-1D:4:10000000c9aaaaaa": {LoggedIn: true, SymmetrixPortKey: []types.PortKey{{DirectorID: "FA-1D", PortID: "4"}}},
+		"FA-3D:1:10000000c9aaaaaa": {LoggedIn: true, SymmetrixPortKey: []types.PortKey{{DirectorID: "FA-3D", PortID: "1"}}},
+		"FA-2D:4:10000000c9bbbbbb": {LoggedIn: false, SymmetrixPortKey: []types.PortKey{{DirectorID: "FA-2D", PortID: "4"}}},
+		"FA-2D:4:10000000c9cccccc": {LoggedIn: true, SymmetrixPortKey: []types.PortKey{{DirectorID: "FA-2D", PortID: "4"}}},
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := r.URL.Path
+		last := path[strings.LastIndex(path, "/")+1:]
+		var out interface{}
+		switch {
+		case strings.HasSuffix(path, XHost+"/host_1"):
+			out = &types.Host{HostID: "host_1", Initiators: []string{"10000000c9aaaaaa", "10000000C9BBBBBB"}, MaskingviewIDs: []string{"mv_1", "mv_2"}}
+		case strings.Contains(path, XMaskingView+"/"):
+			out = &types.MaskingView{MaskingViewID: last, PortGroupID: "pg_1"}
+		case strings.Contains(path, XPortGroup+"/"):
+			out = &types.PortGroup{PortGroupID: last, SymmetrixPortKey: []types.PortKey{{DirectorID: "FA-2D", PortID: "4"}, {DirectorID: "FA-1D", PortID: "4"}}}
+		case strings.HasSuffix(path, XInitiator):
+			list := &types.InitiatorList{}
+			for id := range initiators {
+				list.InitiatorIDs = append(list.InitiatorIDs, id)
+			}
+			out = list
+		case initiators[last] != nil:
+			out = initiators[last]
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"not found"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(out)
+	}))
+}
+
+func TestGetHostConnectivityReport(t *testing.T) {
+	symID := "000000000001"
+	server := newHostConnectivityServer()
+	defer server.Close()
+	c, err := NewClientWithArgs(server.URL, "", true, true, "")
+	assert.NoError(t, err)
+	c.SetAllowedArrays([]string{symID})
+
+	report, err := c.GetHostConnectivityReport(context.Background(), symID, "host_1", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"pg_1"}, report.PortGroupIDs)
+	assert.Equal(t, []types.PortKey{{DirectorID: "FA-1D", PortID: "4"}, {DirectorID: "FA-2D", PortID: "4"}}, report.ExpectedPorts)
+	assert.Equal(t, 1, report.Paths)
+	assert.Equal(t, DefaultMinHostPaths, report.MinPaths)
+	assert.True(t, report.InsufficientPaths)
+	assert.Len(t, report.Initiators, 2)
+
+	hbaA := report.Initiators[0]
+	assert.Equal(t, []string{"FA-1D:4:10000000c9aaaaaa", "FA-3D:1:10000000c9aaaaaa"}, hbaA.InitiatorIDs)
+	assert.Equal(t, 1, hbaA.Paths)
+	assert.Equal(t, []types.PortKey{{DirectorID: "FA-2D", PortID: "4"}}, hbaA.MissingPorts)
+	assert.Equal(t, []types.PortKey{{DirectorID: "FA-3D", PortID: "1"}}, hbaA.OutsidePortGroup)
+
+	hbaB := report.Initiators[1]
+	assert.Equal(t, []string{"FA-2D:4:10000000c9bbbbbb"}, hbaB.InitiatorIDs)
+	assert.Equal(t, 0, hbaB.Paths)
+	assert.Empty(t, hbaB.LoggedInPorts)
+	assert.Len(t, hbaB.MissingPorts, 2)
+
+	report, err = c.GetHostConnectivityReport(context.Background(), symID, "host_1", 1)
+	assert.NoError(t, err)
+	assert.False(t, report.InsufficientPaths)
+
+	_, err = c.GetHostConnectivityReport(context.Background(), symID, "host_2", 1)
+	assert.Error(t, err)
+
+	_, err = c.GetHostConnectivityReport(context.Background(), "000000000002", "host_1", 1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,406 @@
+/*
+ Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pmax
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+	log "github.com/sirupsen/logrus"
+)
+
+// Host flag names, as Unisphere reports them in enabled_flags and disabled_flags
+const (
+	HostFlagVolumeSetAddressing = "Volume_Set_Addressing"
+	HostFlagDisableQResetOnUA   = "Disable_Q_Reset_on_UA"
+	HostFlagEnvironSet          = "Environ_Set"
+	HostFlagAvoidResetBroadcast = "Avoid_Reset_Broadcast"
+	HostFlagOpenVMS             = "OpenVMS"
+	HostFlagSCSI3               = "SCSI_3"
+	HostFlagSpc2ProtocolVersion = "SPC2_Protocol_Version"
+	HostFlagSCSISupport1        = "SCSI_Support1"
+	// HostFlagConsistentLUN is not an overridable flag but is compared and applied like one.
+	HostFlagConsistentLUN = "Consistent_LUN"
+)
+
+// Host flag states used in HostFlagDifference
+const (
+	HostFlagStateEnabled  = "enabled"
+	HostFlagStateDisabled = "disabled"
+	HostFlagStateDefault  = "default"
+)
+
+var hostFlagNames = []string{
+	HostFlagVolumeSetAddressing,
+	HostFlagDisableQResetOnUA,
+	HostFlagEnvironSet,
+	HostFlagAvoidResetBroadcast,
+	HostFlagOpenVMS,
+	HostFlagSCSI3,
+	HostFlagSpc2ProtocolVersion,
+	HostFlagSCSISupport1,
+}
+
+// HostFlagProfile is a named, versioned set of host flag overrides.
+type HostFlagProfile struct {
+	Name    string
+	Version int
+	// Flags maps a host flag name (such as HostFlagSCSI3) to whether it is overridden to enabled or disabled.
+	// Flags not in the map are left at the port default.
+	Flags         map[string]bool
+	ConsistentLUN bool
+}
+
+// HostFlagDifference is a flag whose current state differs from a profile.
+type HostFlagDifference struct {
+	Flag    string
+	Current string
+	Desired string
+}
+
+// HostFlagDrift is a host or host group whose flags differ from its assigned profile.
+type HostFlagDrift struct {
+	// ID is the host or host group ID.
+	ID             string
+	HostGroup      bool
+	Profile        string
+	ProfileVersion int
+	Differences    []HostFlagDifference
+}
+
+// HostFlagAssignments assigns flag profiles to hosts and host groups for AuditHostFlagProfiles.
+type HostFlagAssignments struct {
+	// Hosts maps host IDs to profile names.
+	Hosts map[string]string
+	// HostGroups maps host group IDs to profile names.
+	HostGroups map[string]string
+	// Default, if set, is the profile of every host not listed in Hosts.
+	Default string
+}
+
+// HostFlagAudit is the result of AuditHostFlagProfiles.
+type HostFlagAudit struct {
+	SymmetrixID string
+	// Checked is the number of hosts and host groups compared against a profile.
+	Checked int
+	Drift   []HostFlagDrift
+	// Errors maps host or host group IDs to the error met while auditing them.
+	Errors map[string]string
+}
+
+var (
+	hostFlagProfilesMutex sync.RWMutex
+	hostFlagProfiles      = map[string]HostFlagProfile{
+		"linux-default": {
+			Name:    "linux-default",
+			Version: 1,
+			Flags:   map[string]bool{},
+		},
+		"esxi": {
+			Name:          "esxi",
+			Version:       1,
+			Flags:         map[string]bool{},
+			ConsistentLUN: true,
+		},
+		"aix": {
+			Name:    "aix",
+			Version: 1,
+			Flags: map[string]bool{
+				HostFlagSCSI3:               true,
+				HostFlagSpc2ProtocolVersion: true,
+			},
+		},
+		"windows-cluster": {
+			Name:    "windows-cluster",
+			Version: 1,
+			Flags: map[string]bool{
+				HostFlagSCSI3:               true,
+				HostFlagSpc2ProtocolVersion: true,
+			},
+			ConsistentLUN: true,
+		},
+	}
+)
+
+// RegisterHostFlagProfile adds a profile, or replaces a profile of the same name with a lower version.
+func RegisterHostFlagProfile(profile HostFlagProfile) error {
+	if profile.Name == "" {
+		return fmt.Errorf("host flag profile name is required")
+	}
+	for name := range profile.Flags {
+		if !stringInSlice(name, hostFlagNames) {
+			return fmt.Errorf("host flag profile %s: unknown host flag %s", profile.Name, name)
+		}
+	}
+	hostFlagProfilesMutex.Lock()
+	defer hostFlagProfilesMutex.Unlock()
+	if existing, ok := hostFlagProfiles[profile.Name]; ok && existing.Version >= profile.Version {
+		return fmt.Errorf("host flag profile %s version %d is already registered", profile.Name, existing.Version)
+	}
+	hostFlagProfiles[profile.Name] = profile.clone()
+	return nil
+}
+
+// clone returns a copy of the profile which shares no Flags map with it, so that the registry
+// cannot be changed through a profile passed in or handed out.
+func (profile HostFlagProfile) clone() HostFlagProfile {
+	flags := make(map[string]bool, len(profile.Flags))
+	for name, enabled := range profile.Flags {
+		flags[name] = enabled
+	}
+	profile.Flags = flags
+	return profile
+}
+
+// GetHostFlagProfile returns the registered profile of the given name.
+func GetHostFlagProfile(name string) (HostFlagProfile, error) {
+	hostFlagProfilesMutex.RLock()
+	defer hostFlagProfilesMutex.RUnlock()
+	profile, ok := hostFlagProfiles[name]
+	if !ok {
+		return HostFlagProfile{}, fmt.Errorf("host flag profile %s is not registered", name)
+	}
+	return profile.clone(), nil
+}
+
+// HostFlagProfileNames returns the names of the registered profiles.
+func HostFlagProfileNames() []string {
+	hostFlagProfilesMutex.RLock()
+	defer hostFlagProfilesMutex.RUnlock()
+	names := make([]string, 0, len(hostFlagProfiles))
+	for name := range hostFlagProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseHostFlagList parses a flag list such as "SCSI_3(SC3),SPC2_Protocol_Version(SPC2)" into flag names.
+func parseHostFlagList(flagList string) []string {
+	names := make([]string, 0)
+	for _, flag := range strings.Split(flagList, ",") {
+		flag = strings.TrimSpace(flag)
+		if i := strings.Index(flag, "("); i >= 0 {
+			flag = flag[:i]
+		}
+		for _, name := range hostFlagNames {
+			if strings.EqualFold(flag, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// DiffHostFlags compares the flags of a host or host group, given as Unisphere reports them,
+// with a profile and returns the flags which differ.
+func DiffHostFlags(enabledFlags, disabledFlags string, consistentLUN bool, profile HostFlagProfile) []HostFlagDifference {
+	current := make(map[string]string, len(hostFlagNames))
+	for _, name := range hostFlagNames {
+		current[name] = HostFlagStateDefault
+	}
+	for _, name := range parseHostFlagList(disabledFlags) {
+		current[name] = HostFlagStateDisabled
+	}
+	for _, name := range parseHostFlagList(enabledFlags) {
+		current[name] = HostFlagStateEnabled
+	}
+	differences := make([]HostFlagDifference, 0)
+	for _, name := range hostFlagNames {
+		desired := HostFlagStateDefault
+		if enabled, ok := profile.Flags[name]; ok {
+			desired = HostFlagStateDisabled
+			if enabled {
+				desired = HostFlagStateEnabled
+			}
+		}
+		if current[name] != desired {
+			differences = append(differences, HostFlagDifference{Flag: name, Current: current[name], Desired: desired})
+		}
+	}
+	if consistentLUN != profile.ConsistentLUN {
+		differences = append(differences, HostFlagDifference{
+			Flag:    HostFlagConsistentLUN,
+			Current: fmt.Sprintf("%t", consistentLUN),
+			Desired: fmt.Sprintf("%t", profile.ConsistentLUN),
+		})
+	}
+	return differences
+}
+
+// hostFlagsForDifferences builds the HostFlags which change only the differing flags.
+func hostFlagsForDifferences(differences []HostFlagDifference, profile HostFlagProfile) *types.HostFlags {
+	hostFlags := &types.HostFlags{ConsistentLUN: profile.ConsistentLUN}
+	for _, difference := range differences {
+		flag := &types.HostFlag{
+			Enabled:  difference.Desired == HostFlagStateEnabled,
+			Override: difference.Desired != HostFlagStateDefault,
+		}
+		switch difference.Flag {
+		case HostFlagVolumeSetAddressing:
+			hostFlags.VolumeSetAddressing = flag
+		case HostFlagDisableQResetOnUA:
+			hostFlags.DisableQResetOnUA = flag
+		case HostFlagEnvironSet:
+			hostFlags.EnvironSet = flag
+		case HostFlagAvoidResetBroadcast:
+			hostFlags.AvoidResetBroadcast = flag
+		case HostFlagOpenVMS:
+			hostFlags.OpenVMS = flag
+		case HostFlagSCSI3:
+			hostFlags.SCSI3 = flag
+		case HostFlagSpc2ProtocolVersion:
+			hostFlags.Spc2ProtocolVersion = flag
+		case HostFlagSCSISupport1:
+			hostFlags.SCSISupport1 = flag
+		}
+	}
+	return hostFlags
+}
+
+// ApplyHostFlagProfile changes the flags of a host which differ from the named profile and returns
+// the differences found. The host is not updated if there are none.
+func (c *Client) ApplyHostFlagProfile(ctx context.Context, symID string, hostID string, profileName string) ([]HostFlagDifference, error) {
+	defer c.TimeSpent("ApplyHostFlagProfile", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	profile, err := GetHostFlagProfile(profileName)
+	if err != nil {
+		return nil, err
+	}
+	host, err := c.GetHostByID(ctx, symID, hostID)
+	if err != nil {
+		return nil, err
+	}
+	differences := DiffHostFlags(host.EnabledFlags, host.DisabledFlags, host.ConsistentLun, profile)
+	if len(differences) == 0 {
+		return differences, nil
+	}
+	if _, err := c.UpdateHostFlags(ctx, symID, hostID, hostFlagsForDifferences(differences, profile)); err != nil {
+		return nil, err
+	}
+	log.Infof("Applied host flag profile %s version %d to Host %s: %d flags changed", profile.Name, profile.Version, hostID, len(differences))
+	return differences, nil
+}
+
+// ApplyHostGroupFlagProfile changes the flags of a host group which differ from the named profile and returns
+// the differences found. The host group is not updated if there are none.
+func (c *Client) ApplyHostGroupFlagProfile(ctx context.Context, symID string, hostGroupID string, profileName string) ([]HostFlagDifference, error) {
+	defer c.TimeSpent("ApplyHostGroupFlagProfile", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	profile, err := GetHostFlagProfile(profileName)
+	if err != nil {
+		return nil, err
+	}
+	hostGroup, err := c.GetHostGroupByID(ctx, symID, hostGroupID)
+	if err != nil {
+		return nil, err
+	}
+	differences := DiffHostFlags(hostGroup.EnabledFlags, hostGroup.DisabledFlags, hostGroup.ConsistentLun, profile)
+	if len(differences) == 0 {
+		return differences, nil
+	}
+	if _, err := c.UpdateHostGroupFlags(ctx, symID, hostGroupID, hostFlagsForDifferences(differences, profile)); err != nil {
+		return nil, err
+	}
+	log.Infof("Applied host flag profile %s version %d to HostGroup %s: %d flags changed", profile.Name, profile.Version, hostGroupID, len(differences))
+	return differences, nil
+}
+
+// AuditHostFlagProfiles compares every assigned host and host group with its profile and lists those which drift.
+// Failures to read individual hosts or host groups are recorded in the audit rather than aborting it.
+func (c *Client) AuditHostFlagProfiles(ctx context.Context, symID string, assignments HostFlagAssignments) (*HostFlagAudit, error) {
+	defer c.TimeSpent("AuditHostFlagProfiles", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	hostProfiles := make(map[string]string, len(assignments.Hosts))
+	for hostID, profileName := range assignments.Hosts {
+		hostProfiles[hostID] = profileName
+	}
+	if assignments.Default != "" {
+		hostList, err := c.GetHostList(ctx, symID)
+		if err != nil {
+			return nil, err
+		}
+		for _, hostID := range hostList.HostIDs {
+			if _, ok := hostProfiles[hostID]; !ok {
+				hostProfiles[hostID] = assignments.Default
+			}
+		}
+	}
+	audit := &HostFlagAudit{
+		SymmetrixID: symID,
+		Drift:       make([]HostFlagDrift, 0),
+		Errors:      make(map[string]string),
+	}
+	check := func(id string, hostGroup bool, profileName string) {
+		profile, err := GetHostFlagProfile(profileName)
+		if err != nil {
+			audit.Errors[id] = err.Error()
+			return
+		}
+		var differences []HostFlagDifference
+		if hostGroup {
+			group, err := c.GetHostGroupByID(ctx, symID, id)
+			if err != nil {
+				audit.Errors[id] = err.Error()
+				return
+			}
+			differences = DiffHostFlags(group.EnabledFlags, group.DisabledFlags, group.ConsistentLun, profile)
+		} else {
+			host, err := c.GetHostByID(ctx, symID, id)
+			if err != nil {
+				audit.Errors[id] = err.Error()
+				return
+			}
+			differences = DiffHostFlags(host.EnabledFlags, host.DisabledFlags, host.ConsistentLun, profile)
+		}
+		audit.Checked++
+		if len(differences) > 0 {
+			audit.Drift = append(audit.Drift, HostFlagDrift{
+				ID:             id,
+				HostGroup:      hostGroup,
+				Profile:        profile.Name,
+				ProfileVersion: profile.Version,
+				Differences:    differences,
+			})
+		}
+	}
+	for _, hostID := range sortedKeys(hostProfiles) {
+		check(hostID, false, hostProfiles[hostID])
+	}
+	for _, hostGroupID := range sortedKeys(assignments.HostGroups) {
+		check(hostGroupID, true, assignments.HostGroups[hostGroupID])
+	}
+	return audit, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
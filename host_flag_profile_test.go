@@ -0,0 +1,183 @@
+/*
+ *
+ * Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pmax
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffHostFlags(t *testing.T) {
+	aix, err := GetHostFlagProfile("aix")
+	assert.NoError(t, err)
+	assert.Empty(t, DiffHostFlags("SCSI_3(SC3), SPC2_Protocol_Version(SPC2)", "", false, aix))
+
+	differences := DiffHostFlags("Volume_Set_Addressing(V),SCSI_3(SC3)", "SPC2_Protocol_Version(SPC2)", true, aix)
+	assert.Equal(t, []HostFlagDifference{
+		{Flag: HostFlagVolumeSetAddressing, Current: HostFlagStateEnabled, Desired: HostFlagStateDefault},
+		{Flag: HostFlagSpc2ProtocolVersion, Current: HostFlagStateDisabled, Desired: HostFlagStateEnabled},
+		{Flag: HostFlagConsistentLUN, Current: "true", Desired: "false"},
+	}, differences)
+
+	hostFlags := hostFlagsForDifferences(differences, aix)
+	assert.Equal(t, &types.HostFlag{Enabled: false, Override: false}, hostFlags.VolumeSetAddressing)
+	assert.Equal(t, &types.HostFlag{Enabled: true, Override: true}, hostFlags.Spc2ProtocolVersion)
+	assert.Nil(t, hostFlags.SCSI3)
+	assert.False(t, hostFlags.ConsistentLUN)
+}
+
+func TestRegisterHostFlagProfile(t *testing.T) {
+	assert.Contains(t, HostFlagProfileNames(), "windows-cluster")
+	assert.ErrorContains(t, RegisterHostFlagProfile(HostFlagProfile{}), "name is required")
+	assert.ErrorContains(t, RegisterHostFlagProfile(HostFlagProfile{Name: "bad", Flags: map[string]bool{"nope": true}}), "unknown host flag")
+	assert.ErrorContains(t, RegisterHostFlagProfile(HostFlagProfile{Name: "esxi", Version: 1}), "already registered")
+
+	assert.NoError(t, RegisterHostFlagProfile(HostFlagProfile{Name: "hpux-test", Version: 1, Flags: map[string]bool{HostFlagVolumeSetAddressing: true}}))
+	assert.NoError(t, RegisterHostFlagProfile(HostFlagProfile{Name: "hpux-test", Version: 2, Flags: map[string]bool{HostFlagVolumeSetAddressing: false}}))
+	profile, err := GetHostFlagProfile("hpux-test")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, profile.Version)
+	_, err = GetHostFlagProfile("missing")
+	assert.ErrorContains(t, err, "is not registered")
+
+	// a returned profile cannot change the registered one
+	aix, err := GetHostFlagProfile("aix")
+	assert.NoError(t, err)
+	aix.Flags[HostFlagSCSI3] = false
+	aix.Flags[HostFlagOpenVMS] = true
+	aix, err = GetHostFlagProfile("aix")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{HostFlagSCSI3: true, HostFlagSpc2ProtocolVersion: true}, aix.Flags)
+}
+
+// fakeHostFlagArray serves hosts and host groups with fixed flags and records flag updates.
+type fakeHostFlagArray struct {
+	mu         sync.Mutex
+	hosts      map[string]*types.Host
+	hostGroups map[string]*types.HostGroup
+	updates    map[string]*types.HostFlags
+}
+
+func (f *fakeHostFlagArray) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	path := r.URL.Path
+	last := path[strings.LastIndex(path, "/")+1:]
+	var out interface{}
+	switch {
+	case strings.HasSuffix(path, XHost):
+		list := &types.HostList{}
+		for id := range f.hosts {
+			list.HostIDs = append(list.HostIDs, id)
+		}
+		out = list
+	case r.Method == http.MethodPut && strings.Contains(path, XHostGroup+"/"):
+		payload := &types.UpdateHostGroupParam{}
+		_ = json.NewDecoder(r.Body).Decode(payload)
+		f.updates[last] = payload.EditHostGroupAction.SetHostGroupFlags.HostFlags
+		out = f.hostGroups[last]
+	case r.Method == http.MethodPut:
+		payload := &types.UpdateHostParam{}
+		_ = json.NewDecoder(r.Body).Decode(payload)
+		f.updates[last] = payload.EditHostAction.SetHostFlags.HostFlags
+		out = f.hosts[last]
+	case strings.Contains(path, XHostGroup+"/") && f.hostGroups[last] != nil:
+		out = f.hostGroups[last]
+	case strings.Contains(path, XHost+"/") && f.hosts[last] != nil:
+		out = f.hosts[last]
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"not found"}`))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func newFakeHostFlagArray(t *testing.T) (*fakeHostFlagArray, Pmax) {
+	array := &fakeHostFlagArray{
+		hosts: map[string]*types.Host{
+			"aix_1":   {HostID: "aix_1", EnabledFlags: "SCSI_3(SC3),SPC2_Protocol_Version(SPC2)"},
+			"linux_1": {HostID: "linux_1", EnabledFlags: "Volume_Set_Addressing(V)"},
+			"linux_2": {HostID: "linux_2"},
+		},
+		hostGroups: map[string]*types.HostGroup{
+			"esx_cluster": {HostGroupID: "esx_cluster"},
+		},
+		updates: make(map[string]*types.HostFlags),
+	}
+	server := httptest.NewServer(array)
+	t.Cleanup(server.Close)
+	c, err := NewClientWithArgs(server.URL, "", true, true, "")
+	assert.NoError(t, err)
+	c.SetAllowedArrays([]string{"000000000001"})
+	return array, c
+}
+
+func TestApplyHostFlagProfile(t *testing.T) {
+	ctx := context.Background()
+	array, c := newFakeHostFlagArray(t)
+
+	differences, err := c.ApplyHostFlagProfile(ctx, "000000000001", "aix_1", "aix")
+	assert.NoError(t, err)
+	assert.Empty(t, differences)
+	assert.NotContains(t, array.updates, "aix_1")
+
+	differences, err = c.ApplyHostFlagProfile(ctx, "000000000001", "linux_1", "linux-default")
+	assert.NoError(t, err)
+	assert.Len(t, differences, 1)
+	assert.Equal(t, &types.HostFlag{}, array.updates["linux_1"].VolumeSetAddressing)
+	assert.Nil(t, array.updates["linux_1"].SCSI3)
+
+	differences, err = c.ApplyHostGroupFlagProfile(ctx, "000000000001", "esx_cluster", "esxi")
+	assert.NoError(t, err)
+	assert.Equal(t, []HostFlagDifference{{Flag: HostFlagConsistentLUN, Current: "false", Desired: "true"}}, differences)
+	assert.True(t, array.updates["esx_cluster"].ConsistentLUN)
+
+	_, err = c.ApplyHostFlagProfile(ctx, "000000000001", "linux_1", "missing")
+	assert.ErrorContains(t, err, "is not registered")
+	_, err = c.ApplyHostFlagProfile(ctx, "000000000001", "missing", "aix")
+	assert.Error(t, err)
+}
+
+func TestAuditHostFlagProfiles(t *testing.T) {
+	_, c := newFakeHostFlagArray(t)
+	audit, err := c.AuditHostFlagProfiles(context.Background(), "000000000001", HostFlagAssignments{
+		Hosts:      map[string]string{"aix_1": "aix", "gone": "aix"},
+		HostGroups: map[string]string{"esx_cluster": "esxi"},
+		Default:    "linux-default",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, audit.Checked)
+	drifted := make([]string, 0)
+	for _, drift := range audit.Drift {
+		drifted = append(drifted, drift.ID)
+	}
+	assert.Equal(t, []string{"linux_1", "esx_cluster"}, drifted)
+	assert.True(t, audit.Drift[1].HostGroup)
+	assert.Contains(t, audit.Errors, "gone")
+}
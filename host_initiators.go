@@ -0,0 +1,162 @@
+/*
+ Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pmax
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+	log "github.com/sirupsen/logrus"
+)
+
+var wwnRegex = regexp.MustCompile(`^[0-9a-f]{16}$`)
+
+// InitiatorConflictError is returned by EnsureHostForInitiators when some of the initiators
+// already belong to another host.
+type InitiatorConflictError struct {
+	HostID string
+	// Conflicts maps each conflicting initiator to the host which owns it.
+	Conflicts map[string]string
+}
+
+func (e *InitiatorConflictError) Error() string {
+	initiators := make([]string, 0, len(e.Conflicts))
+	for initiator := range e.Conflicts {
+		initiators = append(initiators, initiator)
+	}
+	sort.Strings(initiators)
+	owners := make([]string, 0, len(initiators))
+	for _, initiator := range initiators {
+		owners = append(owners, initiator+" (host "+e.Conflicts[initiator]+")")
+	}
+	return fmt.Sprintf("initiators of host %s belong to other hosts: %s", e.HostID, strings.Join(owners, ", "))
+}
+
+// NormalizeInitiator returns an initiator in the form the array lists it in a host: IQNs and NQNs are
+// returned as given, FC WWNs are returned as 16 lower case hex digits without separators or prefix.
+func NormalizeInitiator(hba string) (string, error) {
+	hba = strings.TrimSpace(hba)
+	lower := strings.ToLower(hba)
+	if strings.HasPrefix(lower, "iqn.") || strings.HasPrefix(lower, "eui.") || strings.HasPrefix(lower, "naa.") {
+		return lower, nil
+	}
+	if strings.HasPrefix(lower, "nqn.") {
+		return hba, nil
+	}
+	wwn := strings.TrimPrefix(lower, "0x")
+	wwn = strings.NewReplacer(":", "", "-", "").Replace(wwn)
+	if !wwnRegex.MatchString(wwn) {
+		return "", fmt.Errorf("%q is not a WWN, IQN or NQN", hba)
+	}
+	return wwn, nil
+}
+
+// EnsureHostForInitiators makes hostID a host with exactly the given initiators, which may be FC WWNs
+// (in any common notation), IQNs or NQNs. Each initiator must be known to the array. If the host does not
+// exist it is created with hostFlags; otherwise its initiators are updated, and nothing is changed if they
+// already match. An *InitiatorConflictError is returned if any initiator belongs to a different host.
+func (c *Client) EnsureHostForInitiators(ctx context.Context, symID string, hostID string, hbas []string, hostFlags *types.HostFlags) (*types.Host, error) {
+	defer c.TimeSpent("EnsureHostForInitiators", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	if len(hbas) == 0 {
+		return nil, fmt.Errorf("at least one initiator is required for host %s", hostID)
+	}
+	initiators := make([]string, 0, len(hbas))
+	for _, hba := range hbas {
+		initiator, err := NormalizeInitiator(hba)
+		if err != nil {
+			return nil, err
+		}
+		if !stringInSlice(initiator, initiators) {
+			initiators = append(initiators, initiator)
+		}
+	}
+
+	// Resolve each initiator to its array initiator records and look for other owners
+	unresolved := make([]string, 0)
+	conflict := &InitiatorConflictError{HostID: hostID, Conflicts: make(map[string]string)}
+	for _, initiator := range initiators {
+		initList, err := c.GetInitiatorList(ctx, symID, initiator, false, false)
+		if err != nil {
+			return nil, err
+		}
+		found := false
+		for _, initiatorID := range initList.InitiatorIDs {
+			if !initiatorIDMatches(initiatorID, initiator) {
+				continue
+			}
+			found = true
+			record, err := c.GetInitiatorByID(ctx, symID, initiatorID)
+			if err != nil {
+				return nil, err
+			}
+			owner := record.Host
+			if owner == "" {
+				owner = record.HostID
+			}
+			if owner != "" && owner != hostID {
+				conflict.Conflicts[initiator] = owner
+			}
+		}
+		if !found {
+			unresolved = append(unresolved, initiator)
+		}
+	}
+	if len(unresolved) > 0 {
+		return nil, fmt.Errorf("initiators not known to array %s: %s", symID, strings.Join(unresolved, ", "))
+	}
+	if len(conflict.Conflicts) > 0 {
+		log.Error("EnsureHostForInitiators failed: " + conflict.Error())
+		return nil, conflict
+	}
+
+	host, err := c.GetHostByID(ctx, symID, hostID)
+	if err != nil {
+		if !types.IsNotFoundError(err) {
+			return nil, err
+		}
+		return c.CreateHost(ctx, symID, hostID, initiators, hostFlags)
+	}
+
+	// Keep the host's spelling of initiators it already has, so that only real changes are made
+	desired := make([]string, 0, len(initiators))
+	changed := len(initiators) != len(host.Initiators)
+	for _, initiator := range initiators {
+		current := ""
+		for _, hostInitiator := range host.Initiators {
+			if strings.EqualFold(hostInitiator, initiator) {
+				current = hostInitiator
+				break
+			}
+		}
+		if current == "" {
+			current = initiator
+			changed = true
+		}
+		desired = append(desired, current)
+	}
+	if !changed {
+		log.Debugf("host %s already has initiators %v", hostID, host.Initiators)
+		return host, nil
+	}
+	return c.UpdateHostInitiators(ctx, symID, host, desired)
+}
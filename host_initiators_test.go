@@ -0,0 +1,91 @@
+/*
+ *
+ * Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pmax
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dell/gopowermax/v2/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeInitiator(t *testing.T) {
+	tests := map[string]string{
+		"10:00:00:00:C9:AA:AA:AA":                 "10000000c9aaaaaa",
+		"0x10000000c9aaaaaa":                      "10000000c9aaaaaa",
+		" 10-00-00-00-c9-aa-aa-aa ":               "10000000c9aaaaaa",
+		"IQN.1993-08.org.centos:01:5ae577b352a0":  "iqn.1993-08.org.centos:01:5ae577b352a0",
+		"nqn.1988-11.com.dell.mock:00:E6e2d5b871": "nqn.1988-11.com.dell.mock:00:E6e2d5b871",
+	}
+	for in, want := range tests {
+		got, err := NormalizeInitiator(in)
+		assert.NoError(t, err, in)
+		assert.Equal(t, want, got)
+	}
+	_, err := NormalizeInitiator("10:00:00")
+	assert.ErrorContains(t, err, "is not a WWN, IQN or NQN")
+}
+
+func TestEnsureHostForInitiators(t *testing.T) {
+	freeWWN := "10000000c9aaaaaa"
+	ownedIQN := "iqn.1993-08.org.centos:01:5ae577b352a0"
+	freeIQN := "iqn.1993-08.org.centos:01:5ae577b352ff"
+	tests := []struct {
+		name     string
+		hostID   string
+		hbas     []string
+		wantErr  string
+		conflict map[string]string
+	}{
+		{name: "create", hostID: "new-host", hbas: []string{"10:00:00:00:C9:AA:AA:AA", freeWWN}},
+		{name: "unchanged", hostID: "CSI-Test-Node-1-ISCSI", hbas: []string{ownedIQN}},
+		{name: "add initiator", hostID: "CSI-Test-Node-1-ISCSI", hbas: []string{ownedIQN, freeIQN}},
+		{name: "conflict", hostID: "new-host", hbas: []string{freeWWN, ownedIQN}, conflict: map[string]string{ownedIQN: "CSI-Test-Node-1-ISCSI"}},
+		{name: "unknown initiator", hostID: "new-host", hbas: []string{"10000000c9bbbbbb"}, wantErr: "initiators not known to array"},
+		{name: "no initiators", hostID: "new-host", wantErr: "at least one initiator"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newMockReplicationClient(t)
+			_, err := mock.AddInitiator("FA-1D:5:"+freeWWN, freeWWN, "Fibre", []string{"FA-1D:5"}, "")
+			assert.NoError(t, err)
+			_, err = mock.AddInitiator("SE-1E:4:"+freeIQN, freeIQN, "GigE", []string{"SE-1E:4"}, "")
+			assert.NoError(t, err)
+			if tc.name == "unchanged" {
+				mock.InducedErrors.UpdateHostError = true
+			}
+
+			host, err := c.EnsureHostForInitiators(context.Background(), mock.DefaultSymmetrixID, tc.hostID, tc.hbas, nil)
+			if tc.conflict != nil {
+				var conflictErr *InitiatorConflictError
+				assert.True(t, errors.As(err, &conflictErr))
+				assert.Equal(t, tc.conflict, conflictErr.Conflicts)
+				return
+			}
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.hostID, host.HostID)
+		})
+	}
+}
@@ -16,6 +16,7 @@ package pmax
 
 import (
 	"context"
+	"iter"
 	"net/http"
 
 	types "github.com/dell/gopowermax/v2/types/v100"
@@ -101,6 +102,13 @@ type Pmax interface {
 	// DeleteVolumeIDsIterator deletes a Volume iterator.
 	DeleteVolumeIDsIterator(ctx context.Context, iter *types.VolumeIterator) error
 
+	// StreamVolumeIDs returns a sequence of the ids of the volumes matching queryParams, prefetching the next page
+	// and deleting the underlying iterator when the sequence ends.
+	StreamVolumeIDs(ctx context.Context, symID string, queryParams map[string]string) iter.Seq2[string, error]
+
+	// StreamVolumes returns a sequence of the volumes matching queryParams, fetching each page of volumes with bounded concurrency.
+	StreamVolumes(ctx context.Context, symID string, queryParams map[string]string, concurrency int) iter.Seq2[*types.Volume, error]
+
 	// GetVolumeIDList provides a simpler interface that returns a []string of volume ids
 	// of volumes matching the volumeIdentifierMatch (and like) criteria. It is
 	// implemented in terms of GetVolumeIDsIterator, GetVolumeIDsIteratorPage, and DeleteVolumeIDsIterator
@@ -110,11 +118,23 @@ type Pmax interface {
 	// GetVolumeIDListInStorageGroup returns a list of volume IDs that are associated with the StorageGroup
 	GetVolumeIDListInStorageGroup(ctx context.Context, symID string, storageGroupID string) ([]string, error)
 
+	// GetVolumeIDListByQuery returns the ids of the volumes matching a VolumeQuery using the sloprovisioning volume endpoint.
+	GetVolumeIDListByQuery(ctx context.Context, symID string, query *VolumeQuery) ([]string, error)
+
+	// GetVolumesByQuery returns the volumes matching a VolumeQuery using the v1 volumes endpoint.
+	GetVolumesByQuery(ctx context.Context, symID string, query *VolumeQuery) (*types.Volumev1, error)
+
 	// GetVolumeIDListWithParams - Gets a list of volume ids with parameters
 	GetVolumeIDListWithParams(ctx context.Context, symID string, queryParams map[string]string) ([]string, error)
 
 	// GetVolumeByID returns a Volume given the volumeID.
 	GetVolumeByID(ctx context.Context, symID string, volumeID string) (*types.Volume, error)
+	// GetVolumeByNGUID returns the Volume with the given NVMe NGUID.
+	GetVolumeByNGUID(ctx context.Context, symID string, nguid string) (*types.Volume, error)
+	// GetVolumeByWWN returns the Volume with the given WWN, effective WWN or encapsulated WWN.
+	GetVolumeByWWN(ctx context.Context, symID string, wwn string) (*types.Volume, error)
+	// GetVolumesByDevicePaths resolves host device paths or NGUIDs to volume IDs.
+	GetVolumesByDevicePaths(ctx context.Context, symID string, devicePaths []string) ([]DeviceVolume, error)
 
 	// GetVolumesByIdentifier returns a Volume given the volume identifier.
 	GetVolumesByIdentifier(ctx context.Context, symID string, identifier string) (*types.Volumev1, error)
@@ -124,6 +144,12 @@ type Pmax interface {
 
 	// GetVolumesCapacityBulk returns capacity information for all volumes on the array in a single bulk operation.
 	GetVolumesCapacityBulk(ctx context.Context, symID string) (*types.Volumev1, error)
+	// GetCapacityReport rolls up storage group capacity and data reduction per storage group, SRP, service level and tag.
+	GetCapacityReport(ctx context.Context, symID string) (*CapacityReport, error)
+	// GetSLOCompliance returns the service level compliance and recent response times of storage groups.
+	GetSLOCompliance(ctx context.Context, symID string, serviceLevels []string) (*SLOComplianceResult, error)
+	// GetStorageGroupIOLimitReport returns the storage groups with host I/O limits and their observed throughput.
+	GetStorageGroupIOLimitReport(ctx context.Context, symID string, threshold float64) (*StorageGroupIOLimitReport, error)
 
 	// GetStorageGroupIDList returns a list of all the StorageGroup ids.
 	GetStorageGroupIDList(ctx context.Context, symID, storageGroupIDMatch string, like bool) (*types.StorageGroupIDList, error)
@@ -154,6 +180,39 @@ type Pmax interface {
 	// This is done synchronously and doesn't create any jobs
 	UpdateStorageGroupS(ctx context.Context, symID string, storageGroupID string, payload interface{}) error
 
+	// SetStorageGroupIOLimits sets or changes the host I/O limits of a storage group.
+	SetStorageGroupIOLimits(ctx context.Context, symID string, storageGroupID string, maxMBps, maxIOPS int, distribution string) (*types.StorageGroup, error)
+
+	// ClearStorageGroupIOLimits removes the host I/O limits of a storage group.
+	ClearStorageGroupIOLimits(ctx context.Context, symID string, storageGroupID string) (*types.StorageGroup, error)
+
+	// AddChildStorageGroup adds an existing storage group as a child of a parent storage group.
+	AddChildStorageGroup(ctx context.Context, symID string, parentID string, childID string) (*types.StorageGroup, error)
+
+	// RemoveChildStorageGroup removes a child storage group from its parent without deleting it.
+	RemoveChildStorageGroup(ctx context.Context, symID string, parentID string, childID string, force bool) (*types.StorageGroup, error)
+
+	// MergeStorageGroups merges a masked storage group into the masking view of a parent storage group as a child.
+	MergeStorageGroups(ctx context.Context, symID string, parentID string, storageGroupID string) (*types.StorageGroup, error)
+
+	// SplitChildStorageGroup splits a child storage group out of its parent's masking view into a new masking view.
+	SplitChildStorageGroup(ctx context.Context, symID string, parentID string, childID string, maskingViewID string) (*types.StorageGroup, error)
+
+	// GetStorageGroupTree returns a storage group with its volumes and its child storage groups.
+	GetStorageGroupTree(ctx context.Context, symID string, storageGroupID string) (*StorageGroupTree, error)
+
+	// ChangeStorageGroupServiceLevel changes the service level of a storage group, reporting progress until it is compliant.
+	ChangeStorageGroupServiceLevel(ctx context.Context, symID string, storageGroupID string, serviceLevel string, progress StorageGroupChangeFunc) (*types.StorageGroup, error)
+
+	// MoveStorageGroupToSRP moves a storage group to an SRP with enough headroom, reporting progress until it is compliant.
+	MoveStorageGroupToSRP(ctx context.Context, symID string, storageGroupID string, srpID string, progress StorageGroupChangeFunc) (*types.StorageGroup, error)
+
+	// SetStorageGroupCompression enables or disables compression of a storage group.
+	SetStorageGroupCompression(ctx context.Context, symID string, storageGroupID string, enabled bool) (*types.StorageGroup, error)
+
+	// GetCompressionReport lists the uncompressed storage groups of arrays with the estimated savings of compressing them.
+	GetCompressionReport(ctx context.Context, symIDs []string) (*CompressionReport, error)
+
 	// CreateVolumeInStorageGroup takes simplified input arguments to create a volume of a give name and size in a particular storage group.
 	// This method creates a job and waits on the job to complete.
 	CreateVolumeInStorageGroup(ctx context.Context, symID string, storageGroupID string, volumeName string, volumeSize interface{}, volOpts map[string]interface{}) (*types.Volume, error)
@@ -170,6 +229,17 @@ type Pmax interface {
 	// CreateVolume creates volumes using the enhanced Create Volume API with flexible volume creation options.
 	// HTTP header argument is optional and can be used to pass authorization metadata.
 	CreateVolume(ctx context.Context, systemID string, req types.CreateVolumesRequest, opts ...http.Header) (*types.CreateVolumesResponse, error)
+	// CreateVolumesBulk creates volumes in one CreateVolume request and returns the result of each by request ID.
+	CreateVolumesBulk(ctx context.Context, symID string, specs []BulkVolumeSpec, opts ...http.Header) (BulkVolumeResults, error)
+	// CreateVolumesBulkAsync submits a bulk create as an asynchronous job on the array and returns the job.
+	CreateVolumesBulkAsync(ctx context.Context, symID string, specs []BulkVolumeSpec, opts ...http.Header) (*BulkVolumeJob, error)
+	// WaitForBulkVolumeJob waits for a job started by CreateVolumesBulkAsync and returns the result of each volume.
+	WaitForBulkVolumeJob(ctx context.Context, job *BulkVolumeJob, progress func(*types.Job)) (BulkVolumeResults, error)
+	// RetryFailedVolumes creates the volumes of a CreateVolumesBulk result which were not created and adds those
+	// which missed their storage group to it.
+	RetryFailedVolumes(ctx context.Context, symID string, results BulkVolumeResults, opts ...http.Header) (BulkVolumeResults, error)
+	// CreateVolumeFromSnapshot creates a volume in a storage group directly from a snapshot generation.
+	CreateVolumeFromSnapshot(ctx context.Context, symID, sourceVolumeID, snapID string, generation int64, storageGroupID, volumeName string, opts ...http.Header) (*types.Volume, error)
 
 	// GetStorageGroupSnapshots Gets All Storage Group Snapshots
 	GetStorageGroupSnapshots(ctx context.Context, symID string, storageGroupID string, excludeManualSnaps bool, excludeSlSnaps bool) (*types.StorageGroupSnapshot, error)
@@ -189,6 +259,27 @@ type Pmax interface {
 	// DeleteStorageGroupSnapshot Deletes a Storage Group Snapshot snap
 	DeleteStorageGroupSnapshot(ctx context.Context, symID string, storageGroupID string, snapshotID string, snapID string) error
 
+	// ResolveSGSnapshotSnap returns the snap of a Storage Group Snapshot chosen by snap ID, generation or timestamp
+	ResolveSGSnapshotSnap(ctx context.Context, symID, storageGroupID, snapshotID string, selector SGSnapSelector) (*types.StorageGroupSnap, error)
+
+	// LinkSGSnapshot links a Storage Group Snapshot snap to a target storage group and waits for the link to be defined
+	LinkSGSnapshot(ctx context.Context, symID, storageGroupID, snapshotID string, selector SGSnapSelector, link types.LinkSnapshotAction) (*types.StorageGroupSnap, error)
+
+	// RelinkSGSnapshot relinks a Storage Group Snapshot snap to a target storage group and waits for the link to be defined
+	RelinkSGSnapshot(ctx context.Context, symID, storageGroupID, snapshotID string, selector SGSnapSelector, relink types.RelinkSnapshotAction) (*types.StorageGroupSnap, error)
+
+	// UnlinkSGSnapshot unlinks a Storage Group Snapshot snap from a target storage group and waits for the unlink
+	UnlinkSGSnapshot(ctx context.Context, symID, storageGroupID, snapshotID string, selector SGSnapSelector, unlink types.UnlinkSnapshotAction) (*types.StorageGroupSnap, error)
+
+	// RestoreSGSnapshot restores a Storage Group Snapshot snap and waits for the restore to complete
+	RestoreSGSnapshot(ctx context.Context, symID, storageGroupID, snapshotID string, selector SGSnapSelector, restore types.RestoreSnapshotAction) (*types.StorageGroupSnap, error)
+
+	// SetSGSnapshotTTL sets the time to live of a Storage Group Snapshot snap
+	SetSGSnapshotTTL(ctx context.Context, symID, storageGroupID, snapshotID string, selector SGSnapSelector, ttl int32, inHours bool) (*types.StorageGroupSnap, error)
+
+	// SecureSGSnapshot secures a Storage Group Snapshot snap
+	SecureSGSnapshot(ctx context.Context, symID, storageGroupID, snapshotID string, selector SGSnapSelector, secure int32, inHours bool) (*types.StorageGroupSnap, error)
+
 	// DeleteStorageGroup deletes a storage group given a storage group id
 	DeleteStorageGroup(ctx context.Context, symID string, storageGroupID string) error
 
@@ -244,6 +335,10 @@ type Pmax interface {
 
 	// CreatePortGroup creates a port group given the Port Group id and a list of dir/port ids
 	CreatePortGroup(ctx context.Context, symID string, portGroupID string, dirPorts []types.PortKey, protocol string) (*types.PortGroup, error)
+	// CreateNVMeTCPPortGroup creates an NVMe/TCP port group after validating its ports
+	CreateNVMeTCPPortGroup(ctx context.Context, symID string, portGroupID string, dirPorts []types.PortKey) (*types.PortGroup, error)
+	// BuildBalancedPortGroup plans a port group of online ports spread across engines and directors, preferring idle ports
+	BuildBalancedPortGroup(ctx context.Context, symID string, protocol string, count int) (*PortGroupPlan, error)
 
 	// RenamePortGroup renames port group given it is identifier (which is the name)
 	RenamePortGroup(ctx context.Context, symID string, portGroupID string, newName string) (*types.PortGroup, error)
@@ -279,6 +374,22 @@ type Pmax interface {
 	// Initiator IDs do not contain the storage port designations, just the IQN string or FC WWN.
 	// Initiator IDs cannot be a member of more than one host.
 	CreateHost(ctx context.Context, symID string, hostID string, initiatorIDs []string, hostFlags *types.HostFlags) (*types.Host, error)
+	// CreateNVMeHost creates a host from a list of host NQNs (and optional HostFlags).
+	CreateNVMeHost(ctx context.Context, symID string, hostID string, hostNQNs []string, hostFlags *types.HostFlags) (*types.Host, error)
+	// GetNVMeInitiators returns the NVMe initiators, optionally filtered by host NQN and membership of a host.
+	GetNVMeInitiators(ctx context.Context, symID string, hostNQN string, inHost bool) (*types.InitiatorList, error)
+	// GetNVMeHostConnectivity returns the NQNs of an NVMe host and the ports they are connected to.
+	GetNVMeHostConnectivity(ctx context.Context, symID string, hostID string) (*NVMeHostConnectivity, error)
+	// GetHostConnectivityReport returns the ports each host initiator is logged into and the port group ports it is missing
+	GetHostConnectivityReport(ctx context.Context, symID string, hostID string, minPaths int) (*HostConnectivityReport, error)
+	// EnsureHostForInitiators creates or updates a host so that it has exactly the given WWNs, IQNs or NQNs
+	EnsureHostForInitiators(ctx context.Context, symID string, hostID string, hbas []string, hostFlags *types.HostFlags) (*types.Host, error)
+	// ApplyHostFlagProfile changes only the host flags which differ from a named profile
+	ApplyHostFlagProfile(ctx context.Context, symID string, hostID string, profileName string) ([]HostFlagDifference, error)
+	// ApplyHostGroupFlagProfile changes only the host group flags which differ from a named profile
+	ApplyHostGroupFlagProfile(ctx context.Context, symID string, hostGroupID string, profileName string) ([]HostFlagDifference, error)
+	// AuditHostFlagProfiles lists the hosts and host groups whose flags drift from their assigned profiles
+	AuditHostFlagProfiles(ctx context.Context, symID string, assignments HostFlagAssignments) (*HostFlagAudit, error)
 	// DeleteHost deletes a host given the hostID.
 	DeleteHost(ctx context.Context, symID string, hostID string) error
 	// UpdateHostInitiators will update the inititators
@@ -305,6 +416,8 @@ type Pmax interface {
 	GetNVMeTCPTargets(ctx context.Context, symID string) ([]NVMeTCPTarget, error)
 	// GetISCSITargets returns a list of ISCSI Targets for a given sym id
 	GetISCSITargets(ctx context.Context, symID string) ([]ISCSITarget, error)
+	// DiscoverTargets concurrently discovers iSCSI or NVMe/TCP targets, reporting per director and per port errors
+	DiscoverTargets(ctx context.Context, symID string, opts TargetDiscoveryOptions) (*TargetDiscoveryResult, error)
 	// GetISCSIEndpoints returns a list of ISCSI Targets for a given sym id
 	GetISCSIEndpoints(ctx context.Context, symID string) ([]ISCSITarget, error)
 	// CreateHostGroup creates a hostGroup from a list of hostIDs (and optional HostFlags) and  returns a types.HostGroup.
@@ -361,6 +474,16 @@ type Pmax interface {
 	GetSnapshotGenerations(ctx context.Context, symID, volume, SnapID string) (*types.VolumeSnapshotGenerations, error)
 	// GetSnapshotGenerationInfo returns the specific generation info related to a snapshot
 	GetSnapshotGenerationInfo(ctx context.Context, symID, volume, SnapID string, generation int64) (*types.VolumeSnapshotGeneration, error)
+
+	// PruneVolumeSnapshots applies a retention policy to the snapshots of a volume, optionally as a dry run
+	PruneVolumeSnapshots(ctx context.Context, symID, volumeID string, policy SnapshotRetentionPolicy, dryRun bool) (*SnapshotRetentionReport, error)
+
+	// PruneStorageGroupSnapshots applies a retention policy to the manual snapshots of a storage group, optionally as a dry run
+	PruneStorageGroupSnapshots(ctx context.Context, symID, storageGroupID string, policy SnapshotRetentionPolicy, dryRun bool) (*SnapshotRetentionReport, error)
+
+	// CreateConsistentSnapshotGroup snapshots a set of volumes and storage groups at a single point in time, rolling back on failure
+	CreateConsistentSnapshotGroup(ctx context.Context, symID string, request ConsistentSnapshotGroupRequest) (*ConsistentSnapshotGroup, error)
+
 	// GetReplicationCapabilities returns details about SnapVX and SRDF execution capabilities on the Symmetrix array
 	GetReplicationCapabilities(ctx context.Context) (*types.SymReplicationCapabilities, error)
 	// GetPrivVolumeByID returns a Volume structure given the symmetrix and volume ID (volume ID is in WWN format)
@@ -375,6 +498,10 @@ type Pmax interface {
 	ModifyMobilityForVolume(ctx context.Context, symID string, volumeID string, mobility bool) (*types.Volume, error)
 	// ExpandVolume expands the size of an existing volume
 	ExpandVolume(ctx context.Context, symID string, volumeID string, rdfGNo int, volumeSize interface{}, capUnits ...string) (*types.Volume, error)
+	// ExpandVolumeSafe expands a volume and its SRDF partner after pre-checks and waits for both to report the new size.
+	ExpandVolumeSafe(ctx context.Context, symID string, volumeID string, sizeGB float64) (*VolumeExpansion, error)
+	// DecommissionVolume removes a volume from its storage groups, removes its snapshots, frees its tracks and deletes it.
+	DecommissionVolume(ctx context.Context, symID string, volumeID string, force bool) (*VolumeDecommission, error)
 	// GetCreateVolInSGPayload returns a payload to create a volume in a storage group
 	GetCreateVolInSGPayload(volumeSize interface{}, capUnit string, volumeName string, isSync, enableMobility bool, remoteSymID, storageGroupID string, opts ...http.Header) (payload interface{})
 	// GetCreateVolInSGPayloadWithMetaDataHeaders(sizeInCylinders int, volumeName string, isSync bool, remoteSymID, remoteStorageGroupID string, metadata http.Header) (payload interface{})
@@ -424,6 +551,10 @@ type Pmax interface {
 	GetVolumesMetricsByID(ctx context.Context, symID string, volID string, metricsQuery []string, firstAvailableTime, lastAvailableTime int64) (*types.VolumeMetricsIterator, error)
 	// GetFileSystemMetricsByID returns a given FileSystem performance metrics
 	GetFileSystemMetricsByID(ctx context.Context, symID string, fsID string, metricsQuery []string, firstAvailableTime, lastAvailableTime int64) (*types.FileSystemMetricsIterator, error)
+	// GetFEPortPerfKeys returns the available timestamps for the front end port performance of a director
+	GetFEPortPerfKeys(ctx context.Context, symID string, directorID string) (*types.FEPortKeysResult, error)
+	// GetFEPortMetrics returns a given front end port performance metrics
+	GetFEPortMetrics(ctx context.Context, symID string, directorID string, portID string, metricsQuery []string, firstAvailableTime, lastAvailableTime int64) (*types.FEPortMetricsIterator, error)
 
 	// CreateMigrationEnvironment creates a migration environment
 	CreateMigrationEnvironment(ctx context.Context, sourceSymID, remoteSymID string) (*types.MigrationEnv, error)
@@ -455,6 +586,21 @@ type Pmax interface {
 		complianceCountCritical int64, optionalPayload map[string]interface{}) (*types.SnapshotPolicy, error)
 	// UpdateSnapshotPolicy is a general method to update a SnapshotPolicy (PUT operation) based on the action using a UpdateSnapshotPolicyPayload.
 	UpdateSnapshotPolicy(ctx context.Context, symID string, action string, snapshotPolicyID string, optionalPayload map[string]interface{}) error
+	// GetSnapshotPolicyStorageGroupList returns the names of the storage groups associated with a SnapshotPolicy
+	GetSnapshotPolicyStorageGroupList(ctx context.Context, symID string, snapshotPolicyID string) (*types.SnapshotPolicyStorageGroupList, error)
+	// GetSnapshotPolicyCompliance returns the good, failed and missing snapshot counts and compliance state of a storage group against a SnapshotPolicy.
+	GetSnapshotPolicyCompliance(ctx context.Context, symID, snapshotPolicyID, storageGroupID string) (*SnapshotPolicyCompliance, error)
+	// GetSnapshotPolicyComplianceSummary returns the compliance of every storage group of every SnapshotPolicy on the array.
+	GetSnapshotPolicyComplianceSummary(ctx context.Context, symID string) (*SnapshotPolicyComplianceSummary, error)
+	// CreateCloudSnapshotPolicy creates a cloud SnapshotPolicy which retains snapshots in a cloud provider.
+	CreateCloudSnapshotPolicy(ctx context.Context, symID string, snapshotPolicyID string, interval string, offsetMins int32,
+		complianceCountWarn int64, complianceCountCritical int64, cloudDetails types.CloudSnapshotPolicyDetails) (*types.SnapshotPolicy, error)
+	// ModifyCloudSnapshotPolicy modifies a cloud SnapshotPolicy.
+	ModifyCloudSnapshotPolicy(ctx context.Context, symID string, snapshotPolicyID string, modify types.ModifySnapshotPolicyParam) error
+	// GetCloudProviderList returns the names of the cloud providers configured on the Symmetrix.
+	GetCloudProviderList(ctx context.Context, symID string) (*types.CloudProviderList, error)
+	// GetStorageGroupCloudSnapshots returns the snapshots of a storage group which are resident in a cloud provider.
+	GetStorageGroupCloudSnapshots(ctx context.Context, symID string, storageGroupID string) (*types.CloudSnapshotList, error)
 
 	// GetFileSystemList get file system list on a symID
 	GetFileSystemList(ctx context.Context, symID string, query types.QueryParams) (*types.FileSystemIterator, error)
@@ -31,6 +31,7 @@ const (
 	StorageGroup          = "/StorageGroup"
 	Volume                = "/Volume"
 	FileSystem            = "/file/filesystem"
+	FEPort                = "/FEPort"
 	Metrics               = "/metrics"
 	Keys                  = "/keys"
 	Array                 = "/Array"
@@ -252,3 +253,108 @@ func (c *Client) GetFileSystemMetricsByID(ctx context.Context, symID string, fsI
 	}
 	return metricsList, nil
 }
+
+// GetFEPortPerfKeys returns the available timestamps for the front end port performance of a director
+func (c *Client) GetFEPortPerfKeys(ctx context.Context, symID string, directorID string) (*types.FEPortKeysResult, error) {
+	defer c.TimeSpent("GetFEPortPerfKeys", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	URL := RESTPrefix + Performance + FEPort + Keys
+	ctx, cancel := c.GetTimeoutContext(ctx)
+	defer cancel()
+	params := types.FEPortKeysParam{
+		SymmetrixID: symID,
+		DirectorID:  directorID,
+	}
+	resp, err := c.api.DoAndGetResponseBody(ctx, http.MethodPost, URL, c.getDefaultHeaders(), params)
+	if err != nil {
+		log.Errorf("GetFEPortPerfKeys failed: %s", err.Error())
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if err = c.checkResponse(resp); err != nil {
+		return nil, err
+	}
+	portInfo := &types.FEPortKeysResult{}
+	decoder := json.NewDecoder(resp.Body)
+	if err = decoder.Decode(portInfo); err != nil {
+		return nil, err
+	}
+	return portInfo, nil
+}
+
+// GetFEPortMetrics returns a given front end port performance metrics
+func (c *Client) GetFEPortMetrics(ctx context.Context, symID string, directorID string, portID string, metricsQuery []string, firstAvailableTime, lastAvailableTime int64) (*types.FEPortMetricsIterator, error) {
+	defer c.TimeSpent("GetFEPortMetrics", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	URL := RESTPrefix + Performance + FEPort + Metrics
+	ctx, cancel := c.GetTimeoutContext(ctx)
+	defer cancel()
+	params := types.FEPortMetricsParam{
+		SymmetrixID: symID,
+		DirectorID:  directorID,
+		PortID:      portID,
+		StartDate:   firstAvailableTime,
+		EndDate:     lastAvailableTime,
+		DataFormat:  Average,
+		Metrics:     metricsQuery,
+	}
+	resp, err := c.api.DoAndGetResponseBody(ctx, http.MethodPost, URL, c.getDefaultHeaders(), params)
+	if err != nil {
+		log.Errorf("GetFEPortMetrics failed: %s", err.Error())
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if err = c.checkResponse(resp); err != nil {
+		return nil, err
+	}
+	metricsList := &types.FEPortMetricsIterator{}
+	decoder := json.NewDecoder(resp.Body)
+	if err = decoder.Decode(metricsList); err != nil {
+		return nil, err
+	}
+	return metricsList, nil
+}
+
+// getStorageGroupPerfInfos returns the performance data availability of each storage group of an array with data.
+func (c *Client) getStorageGroupPerfInfos(ctx context.Context, symID string) (map[string]types.StorageGroupInfo, error) {
+	keys, err := c.GetStorageGroupPerfKeys(ctx, symID)
+	if err != nil {
+		return nil, err
+	}
+	available := make(map[string]types.StorageGroupInfo)
+	for _, info := range keys.StorageGroupInfos {
+		available[info.StorageGroupID] = info
+	}
+	return available, nil
+}
+
+// averageWindowMetrics fetches the samples of a performance key over window, ending at the key's last available
+// sample and starting no earlier than its first, and returns them with the average of each of values over them.
+// fetch is a metrics query such as GetStorageGroupMetrics or GetFEPortMetrics bound to the key. The averages are
+// nil if there are no samples.
+func averageWindowMetrics[T any](first, last int64, window time.Duration, fetch func(start, end int64) ([]T, error),
+	values ...func(T) float64,
+) ([]float64, []T, error) {
+	start := last - window.Milliseconds()
+	if start < first {
+		start = first
+	}
+	samples, err := fetch(start, last)
+	if err != nil || len(samples) == 0 {
+		return nil, samples, err
+	}
+	averages := make([]float64, len(values))
+	for _, sample := range samples {
+		for i, value := range values {
+			averages[i] += value(sample)
+		}
+	}
+	for i := range averages {
+		averages[i] /= float64(len(samples))
+	}
+	return averages, samples, nil
+}
@@ -73,6 +73,7 @@ const (
 	DefaultFSID                   = "64xxx7a6-03b5-xxx-xxx-0zzzz8200208"
 	DefaultFSName                 = "fs-ds-1"
 	DefaultNFSServerID            = "678xx790-115e-xxxx-xxxx-0zzzz200205"
+	DefaultCloudProvider          = "ECS-Cloud-1"
 )
 
 const (
@@ -123,6 +124,11 @@ var Data struct {
 	SnapNameToSnapID  map[string]int64 // maps "volID:snapName" to a numeric snap_id
 	NextSnapID        int64            // counter for generating unique snap_ids
 
+	// Snapshot Policies created with cloud details, keyed by policy name
+	SnapshotPolicyIDToSnapshotPolicy map[string]*types.SnapshotPolicy
+	CloudProviders                   []string
+	StorageGroupIDToCloudSnapshots   map[string][]types.CloudSnapshot
+
 	// SRDF
 	StorageGroupIDToRDFStorageGroup map[string]*types.RDFStorageGroup
 	AsyncRDFGroup                   *types.RDFGroup
@@ -260,6 +266,8 @@ type inducedErrors struct {
 	CreateSnapshotPolicyError              bool
 	ModifySnapshotPolicyError              bool
 	DeleteSnapshotPolicyError              bool
+	GetCloudProviderListError              bool
+	GetCloudSnapshotListError              bool
 	GetFileSystemListError                 bool
 	GetNFSExportListError                  bool
 	GetNASServerListError                  bool
@@ -470,6 +478,8 @@ func Reset() {
 	InducedErrors.CreateSnapshotPolicyError = false
 	InducedErrors.ModifySnapshotPolicyError = false
 	InducedErrors.DeleteSnapshotPolicyError = false
+	InducedErrors.GetCloudProviderListError = false
+	InducedErrors.GetCloudSnapshotListError = false
 	InducedErrors.GetStorageGroupSnapshotError = false
 	InducedErrors.CreateSnapshotPolicyError = false
 	InducedErrors.GetStorageGroupSnapshotSnapError = false
@@ -519,6 +529,21 @@ func Reset() {
 	Data.SnapNameToSnapID = make(map[string]int64)
 	Data.NextSnapID = 100661523201 // start with a realistic snap_id value
 	Data.NextVolumeIndex = 187     // start volume IDs from 00187
+	Data.SnapshotPolicyIDToSnapshotPolicy = make(map[string]*types.SnapshotPolicy)
+	Data.CloudProviders = []string{DefaultCloudProvider}
+	Data.StorageGroupIDToCloudSnapshots = map[string][]types.CloudSnapshot{
+		"CSI-Test-SG-1": {
+			{
+				SnapshotID:         "cloud-snap-1",
+				SnapshotName:       "CloudWeekly",
+				CloudProviderName:  DefaultCloudProvider,
+				SnapshotPolicyName: "CloudWeekly",
+				TimestampUtc:       1671091500000,
+				ExpiryTimestampUtc: 1671955500000,
+				State:              "Uploaded",
+			},
+		},
+	}
 	Data.StorageGroupIDToRDFStorageGroup = make(map[string]*types.RDFStorageGroup)
 	Data.HostGroupIDToHostGroup = make(map[string]*types.HostGroup)
 	Data.FileSysIDToFileSystem = make(map[string]*types.FileSystem)
@@ -789,6 +814,10 @@ func getRouter() http.Handler {
 	router.HandleFunc(PREFIX+"/replication/symmetrix/{symid}/snapshot_policy/{snapshotPolicyId}", HandleGetSnapshotPolicy)
 	router.HandleFunc(PREFIX+"/replication/symmetrix/{symid}/snapshot_policy", HandleCreateSnapshotPolicy)
 
+	// Cloud snapshots
+	router.HandleFunc(PREFIX+"/replication/symmetrix/{symid}/cloud_provider", HandleCloudProvider)
+	router.HandleFunc(PREFIX+"/replication/symmetrix/{symid}/storagegroup/{id}/cloud_snapshot", HandleStorageGroupCloudSnapshot)
+
 	// File APIs
 	router.HandleFunc(PREFIX+"/file/symmetrix/{symid}/file_system/{fsID}", HandleFileSystem)
 	router.HandleFunc(PREFIX+"/file/symmetrix/{symid}/file_system", HandleFileSystem)
@@ -946,6 +975,10 @@ func handleGetSnapshotPolicy(w http.ResponseWriter, r *http.Request) {
 		writeError(w, "Could not delete Snapshot Policy : induced error", http.StatusBadRequest)
 		return
 	}
+	if snapPolicy, ok := Data.SnapshotPolicyIDToSnapshotPolicy[mux.Vars(r)["snapshotPolicyId"]]; ok {
+		handleStoredSnapshotPolicy(w, r, snapPolicy)
+		return
+	}
 	if r.Method == http.MethodGet {
 
 		snapPolicy := &types.SnapshotPolicy{
@@ -1011,6 +1044,15 @@ func handleCreateSnapshotPolicy(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, snapPolicyList)
 	}
 	if r.Method == http.MethodPost {
+		createParam := &types.CreateSnapshotPolicyParam{}
+		if err := json.NewDecoder(r.Body).Decode(createParam); err != nil {
+			writeError(w, "InvalidJson", http.StatusBadRequest)
+			return
+		}
+		if createParam.CloudSnapshotPolicyDetails != nil {
+			handleCreateCloudSnapshotPolicy(w, mux.Vars(r)["symid"], createParam)
+			return
+		}
 
 		snapPolicy := &types.SnapshotPolicy{
 			SymmetrixID:            "000197902572",
@@ -1029,6 +1071,143 @@ func handleCreateSnapshotPolicy(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handleCreateCloudSnapshotPolicy stores a cloud snapshot policy so that later requests see it
+func handleCreateCloudSnapshotPolicy(w http.ResponseWriter, symID string, createParam *types.CreateSnapshotPolicyParam) {
+	details := createParam.CloudSnapshotPolicyDetails
+	snapPolicy := &types.SnapshotPolicy{
+		SymmetrixID:             symID,
+		SnapshotPolicyName:      createParam.SnapshotPolicyName,
+		IntervalMinutes:         intervalToMinutes(createParam.Interval),
+		OffsetMinutes:           int64(createParam.OffsetMins),
+		ProviderName:            details.CloudProviderName,
+		RetentionDays:           int64(details.CloudRetentionDays),
+		ComplianceCountWarning:  createParam.ComplianceCountWarning,
+		ComplianceCountCritical: createParam.ComplianceCountCritical,
+		Type:                    "cloud",
+	}
+	Data.SnapshotPolicyIDToSnapshotPolicy[snapPolicy.SnapshotPolicyName] = snapPolicy
+	writeJSON(w, snapPolicy)
+}
+
+// handleStoredSnapshotPolicy serves GET, PUT and DELETE for a snapshot policy held in the mock cache
+func handleStoredSnapshotPolicy(w http.ResponseWriter, r *http.Request, snapPolicy *types.SnapshotPolicy) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, snapPolicy)
+	case http.MethodDelete:
+		delete(Data.SnapshotPolicyIDToSnapshotPolicy, snapPolicy.SnapshotPolicyName)
+	case http.MethodPut:
+		updateParam := &types.UpdateSnapshotPolicyParam{}
+		if err := json.NewDecoder(r.Body).Decode(updateParam); err != nil {
+			writeError(w, "InvalidJson", http.StatusBadRequest)
+			return
+		}
+		switch updateParam.Action {
+		case "Modify":
+			modify := updateParam.ModifySnapshotPolicyParam
+			if modify == nil {
+				writeError(w, "modify parameters are required", http.StatusBadRequest)
+				return
+			}
+			if modify.IntervalMinutes != 0 {
+				snapPolicy.IntervalMinutes = modify.IntervalMinutes
+			}
+			if modify.OffsetMins != 0 {
+				snapPolicy.OffsetMinutes = int64(modify.OffsetMins)
+			}
+			if modify.ComplianceCountWarning != 0 {
+				snapPolicy.ComplianceCountWarning = modify.ComplianceCountWarning
+			}
+			if modify.ComplianceCountCritical != 0 {
+				snapPolicy.ComplianceCountCritical = modify.ComplianceCountCritical
+			}
+			if modify.CloudRetentionDays != 0 {
+				snapPolicy.RetentionDays = int64(modify.CloudRetentionDays)
+			}
+			if modify.SnapshotPolicyName != "" && modify.SnapshotPolicyName != snapPolicy.SnapshotPolicyName {
+				delete(Data.SnapshotPolicyIDToSnapshotPolicy, snapPolicy.SnapshotPolicyName)
+				snapPolicy.SnapshotPolicyName = modify.SnapshotPolicyName
+				Data.SnapshotPolicyIDToSnapshotPolicy[snapPolicy.SnapshotPolicyName] = snapPolicy
+			}
+		case "Suspend":
+			snapPolicy.Suspended = true
+		case "Resume":
+			snapPolicy.Suspended = false
+		}
+		writeJSON(w, snapPolicy)
+	default:
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// intervalToMinutes converts a snapshot policy interval such as "1 Hour" or "7 Days" to minutes
+func intervalToMinutes(interval string) int64 {
+	fields := strings.Fields(interval)
+	if len(fields) != 2 {
+		return 0
+	}
+	n, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0
+	}
+	switch strings.TrimSuffix(strings.ToLower(fields[1]), "s") {
+	case "minute":
+		return n
+	case "hour":
+		return n * 60
+	case "day":
+		return n * 24 * 60
+	}
+	return 0
+}
+
+// GET /replication/symmetrix/{symid}/cloud_provider
+func HandleCloudProvider(w http.ResponseWriter, r *http.Request) {
+	mockCacheMutex.Lock()
+	defer mockCacheMutex.Unlock()
+	handleCloudProvider(w, r)
+}
+
+func handleCloudProvider(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if InducedErrors.GetCloudProviderListError {
+		writeError(w, "Could not get Cloud Provider List: induced error", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, &types.CloudProviderList{CloudProviderIDs: Data.CloudProviders})
+}
+
+// GET /replication/symmetrix/{symid}/storagegroup/{id}/cloud_snapshot
+func HandleStorageGroupCloudSnapshot(w http.ResponseWriter, r *http.Request) {
+	mockCacheMutex.Lock()
+	defer mockCacheMutex.Unlock()
+	handleStorageGroupCloudSnapshot(w, r)
+}
+
+func handleStorageGroupCloudSnapshot(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if InducedErrors.GetCloudSnapshotListError {
+		writeError(w, "Could not get Cloud Snapshot List: induced error", http.StatusBadRequest)
+		return
+	}
+	sgID := mux.Vars(r)["id"]
+	if Data.StorageGroupIDToStorageGroup[sgID] == nil {
+		writeError(w, "Storage Group "+sgID+" not found", http.StatusNotFound)
+		return
+	}
+	snapshots := Data.StorageGroupIDToCloudSnapshots[sgID]
+	if snapshots == nil {
+		snapshots = make([]types.CloudSnapshot, 0)
+	}
+	writeJSON(w, &types.CloudSnapshotList{CloudSnapshots: snapshots})
+}
+
 // GET univmax/restapi/100/replication/symmetrix/{symID}/rdf_director/{dir}/port?online=true
 // GET univmax/restapi/100/replication/symmetrix/{symID}/rdf_director/{dir}/port/{port}
 func HandleRDFPort(w http.ResponseWriter, r *http.Request) {
@@ -1631,15 +1810,28 @@ func handleVolumes(w http.ResponseWriter, r *http.Request) {
 
 		filter := queryParams.Get("filter")
 		identifier := ""
-		if strings.Contains(filter, "identifier EQ ") {
+		if strings.HasPrefix(filter, "identifier EQ ") {
 			identifier = strings.TrimPrefix(filter, "identifier EQ ")
 		}
+		// WWN and NGUID filters match regardless of case
+		wwnField, wwnValue := "", ""
+		for _, field := range []string{"effective_wwn", "wwn", "encapsulated_wwn", "nguid"} {
+			if strings.HasPrefix(filter, field+" EQ ") {
+				wwnField, wwnValue = field, strings.TrimPrefix(filter, field+" EQ ")
+			}
+		}
 
 		// Search mock data for matching volumes by identifier
 		result := &types.Volumev1{
 			Volumes: make([]types.VolumeEnhanced, 0),
 		}
 		for _, vol := range Data.VolumeIDToVolume {
+			if vol != nil && wwnField != "" {
+				values := map[string]string{"effective_wwn": vol.EffectiveWWN, "wwn": vol.WWN, "encapsulated_wwn": vol.EncapsulatedWWN, "nguid": vol.NGUID}
+				if !strings.EqualFold(values[wwnField], wwnValue) {
+					continue
+				}
+			}
 			if vol != nil && (identifier == "" || vol.VolumeIdentifier == identifier) {
 				sgIDs := make([]types.StorageGroupID, 0)
 				for _, sg := range vol.StorageGroupIDList {
@@ -1652,6 +1844,12 @@ func handleVolumes(w http.ResponseWriter, r *http.Request) {
 					StorageGroups: sgIDs,
 					CapCyl:        float64(vol.CapacityCYL),
 				}
+				if strings.Contains(selectParam, "wwn") {
+					enhanced.WWN = vol.WWN
+					enhanced.EffectiveWWN = vol.EffectiveWWN
+					enhanced.EncapsulatedWWN = vol.EncapsulatedWWN
+					enhanced.NGUID = vol.NGUID
+				}
 				result.Volumes = append(result.Volumes, enhanced)
 			}
 		}
@@ -1666,11 +1864,28 @@ func handleVolumes(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func handleVolumesCapacityBulk(w http.ResponseWriter, _ *http.Request) {
+func handleVolumesCapacityBulk(w http.ResponseWriter, r *http.Request) {
 	if InducedErrors.GetVolumesCapacityBulkError {
 		writeError(w, "Error getting volumes capacity bulk: induced error", http.StatusRequestTimeout)
 		return
 	}
+	// a storage group filter is answered from the mock volumes, using their allocated percent as used capacity
+	if sgID, ok := strings.CutPrefix(r.URL.Query().Get("filter"), "storage_groups.id EQ "); ok {
+		result := &types.Volumev1{Volumes: make([]types.VolumeEnhanced, 0)}
+		for _, vol := range Data.VolumeIDToVolume {
+			if vol == nil || !stringInSlice(sgID, vol.StorageGroupIDList) {
+				continue
+			}
+			result.Volumes = append(result.Volumes, types.VolumeEnhanced{
+				ID:                      vol.VolumeID,
+				CapGB:                   vol.CapacityGB,
+				EffectiveUsedCapacityGB: vol.CapacityGB * float64(vol.AllocatedPercent) / 100,
+				StorageGroups:           []types.StorageGroupID{{StorageGroupID: sgID}},
+			})
+		}
+		writeJSON(w, result)
+		return
+	}
 	result := &types.Volumev1{
 		Volumes: []types.VolumeEnhanced{
 			{
@@ -1701,6 +1916,17 @@ func handleVolume(w http.ResponseWriter, r *http.Request) {
 			var like bool
 			queryParams := r.URL.Query()
 			volumeIdentifier := queryParams.Get("volume_identifier")
+			nguid := queryParams.Get("nguid")
+			wwn := queryParams.Get("wwn")
+			effectiveWWN := queryParams.Get("effective_wwn")
+			encapsulatedWWN := queryParams.Get("encapsulated_wwn")
+			storageGroupIDs := make([]string, 0)
+			if storageGroupID := queryParams.Get("storageGroupId"); storageGroupID != "" {
+				storageGroupIDs = append(storageGroupIDs, storageGroupID)
+				if sg, ok := Data.StorageGroupIDToStorageGroup[storageGroupID]; ok {
+					storageGroupIDs = append(storageGroupIDs, sg.ChildStorageGroup...)
+				}
+			}
 			if strings.Contains(volumeIdentifier, "<like>") {
 				like = true
 				volumeIdentifier = strings.TrimPrefix(volumeIdentifier, "<like>")
@@ -1719,6 +1945,21 @@ func handleVolume(w http.ResponseWriter, r *http.Request) {
 						}
 					}
 				}
+				if nguid != "" && !strings.EqualFold(vol.NGUID, nguid) {
+					continue
+				}
+				if wwn != "" && !strings.EqualFold(vol.WWN, wwn) {
+					continue
+				}
+				if effectiveWWN != "" && !strings.EqualFold(vol.EffectiveWWN, effectiveWWN) {
+					continue
+				}
+				if encapsulatedWWN != "" && !strings.EqualFold(vol.EncapsulatedWWN, encapsulatedWWN) {
+					continue
+				}
+				if len(storageGroupIDs) > 0 && !volumeInStorageGroups(vol, storageGroupIDs) {
+					continue
+				}
 				Data.VolumeIDIteratorList = append(Data.VolumeIDIteratorList, vol.VolumeID)
 			}
 			if Debug {
@@ -1839,7 +2080,8 @@ func deleteVolume(volID string) error {
 
 func returnVolume(w http.ResponseWriter, volID string, remote bool) {
 	if volID != "" {
-		if vol, ok := Data.VolumeIDToVolume[volID]; ok {
+		// Deleted volumes are left in the cache as nil
+		if vol, ok := Data.VolumeIDToVolume[volID]; ok && vol != nil {
 			newVol := new(types.Volume)
 			err := copier.Copy(newVol, vol)
 			if err != nil {
@@ -2184,6 +2426,8 @@ func handleStorageGroup(w http.ResponseWriter, r *http.Request) {
 			if editPayload.RemoveVolumeParam != nil {
 				removeVolumeFromStorageGroup(w, editPayload.RemoveVolumeParam.VolumeIDs, sgID)
 			}
+			editStorageGroupSettings(w, sgID, &editPayload)
+			editStorageGroupCascade(w, sgID, &editPayload)
 		} else {
 			// for apiVersion 91
 			updateSGPayload := &types.UpdateStorageGroupPayload{}
@@ -2210,6 +2454,8 @@ func handleStorageGroup(w http.ResponseWriter, r *http.Request) {
 			if editPayload.RemoveVolumeParam != nil {
 				removeVolumeFromStorageGroup(w, editPayload.RemoveVolumeParam.VolumeIDs, sgID)
 			}
+			editStorageGroupSettings(w, sgID, &editPayload)
+			editStorageGroupCascade(w, sgID, &editPayload)
 		}
 	case http.MethodPost:
 		if InducedErrors.CreateStorageGroupError {
@@ -2624,6 +2870,10 @@ func handleCreateVolume(w http.ResponseWriter, r *http.Request) {
 				}
 				if srcVolID != "" {
 					if srcVol, ok := Data.VolumeIDToVolume[srcVolID]; ok && srcVol != nil {
+						// Without new_volume_attributes the volume inherits the source volume size
+						if capCyl == 0 {
+							capCyl = srcVol.CapacityCYL
+						}
 						if capCyl > 0 && srcVol.CapacityCYL > 0 && capCyl < srcVol.CapacityCYL {
 							log.Printf("CreateVolume: snapshot restore target size %d CYL < source size %d CYL", capCyl, srcVol.CapacityCYL)
 							failedResult := createVolumeFailedResult(vol.RequestID,
@@ -2701,6 +2951,17 @@ func handleCreateVolume(w http.ResponseWriter, r *http.Request) {
 		if failedCount > 0 {
 			httpStatus = http.StatusInternalServerError
 		}
+		if createReq.ExecutionOption == types.ExecutionOptionAsynchronous {
+			// The volumes above are created by the time the job is first read
+			finalState := types.JobStatusSucceeded
+			if failedCount > 0 {
+				finalState = types.JobStatusFailed
+			}
+			jobID := fmt.Sprintf("CreateVolumes-%d", len(Data.JobIDToMockJob)+1)
+			job := newMockJob(jobID, types.JobStatusRunning, finalState, "")
+			writeJSON(w, &job.Job)
+			return
+		}
 		response := &types.CreateVolumesResponse{
 			HTTPStatusCode: httpStatus,
 			Summary: types.ResponseSummary{
@@ -3463,6 +3724,160 @@ func ReturnStorageGroup(w http.ResponseWriter, sgID string, remote bool) {
 	returnStorageGroup(w, sgID, remote)
 }
 
+// linkChildStorageGroup makes child a child storage group of parent.
+func linkChildStorageGroup(parent, child *types.StorageGroup) {
+	parent.ChildStorageGroup = append(parent.ChildStorageGroup, child.StorageGroupID)
+	parent.NumOfChildSGs = len(parent.ChildStorageGroup)
+	parent.Type = "Parent"
+	child.ParentStorageGroup = append(child.ParentStorageGroup, parent.StorageGroupID)
+	child.NumOfParentSGs = len(child.ParentStorageGroup)
+	child.Type = "Child"
+}
+
+// unlinkChildStorageGroup removes child from the child storage groups of parent.
+func unlinkChildStorageGroup(parent, child *types.StorageGroup) {
+	parent.ChildStorageGroup = removeString(parent.ChildStorageGroup, child.StorageGroupID)
+	parent.NumOfChildSGs = len(parent.ChildStorageGroup)
+	if parent.NumOfChildSGs == 0 {
+		parent.Type = "Standalone"
+	}
+	child.ParentStorageGroup = removeString(child.ParentStorageGroup, parent.StorageGroupID)
+	child.NumOfParentSGs = len(child.ParentStorageGroup)
+	if child.NumOfParentSGs == 0 {
+		child.Type = "Standalone"
+	}
+}
+
+// removeString returns values without value.
+func removeString(values []string, value string) []string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// editStorageGroupCascade applies the child storage group changes of a PUT StorageGroup payload to the cached storage groups.
+func editStorageGroupCascade(w http.ResponseWriter, sgID string, editPayload *types.EditStorageGroupActionParam) {
+	var childIDs []string
+	switch {
+	case editPayload.ExpandStorageGroupParam != nil && editPayload.ExpandStorageGroupParam.AddExistingStorageGroupParam != nil:
+		childIDs = editPayload.ExpandStorageGroupParam.AddExistingStorageGroupParam.StorageGroupIDs
+	case editPayload.RemoveStorageGroupParam != nil:
+		childIDs = editPayload.RemoveStorageGroupParam.StorageGroupIDs
+	case editPayload.MergeStorageGroupParam != nil:
+		childIDs = []string{editPayload.MergeStorageGroupParam.StorageGroupID}
+	case editPayload.SplitChildStorageGroupParam != nil:
+		childIDs = []string{editPayload.SplitChildStorageGroupParam.StorageGroupID}
+	default:
+		return
+	}
+	parent, ok := Data.StorageGroupIDToStorageGroup[sgID]
+	if !ok {
+		writeError(w, "StorageGroup not found", http.StatusNotFound)
+		return
+	}
+	for _, childID := range childIDs {
+		child, ok := Data.StorageGroupIDToStorageGroup[childID]
+		if !ok {
+			writeError(w, "StorageGroup not found", http.StatusNotFound)
+			return
+		}
+		switch {
+		case editPayload.RemoveStorageGroupParam != nil:
+			unlinkChildStorageGroup(parent, child)
+		case editPayload.SplitChildStorageGroupParam != nil:
+			unlinkChildStorageGroup(parent, child)
+			maskingViewID := editPayload.SplitChildStorageGroupParam.MaskingViewID
+			hostID, portGroupID := "", ""
+			if len(parent.MaskingView) > 0 {
+				if mv, ok := Data.MaskingViewIDToMaskingView[parent.MaskingView[0]]; ok {
+					hostID, portGroupID = mv.HostID, mv.PortGroupID
+				}
+			}
+			newMaskingView(maskingViewID, childID, hostID, portGroupID)
+			child.MaskingView = append(child.MaskingView, maskingViewID)
+			child.NumOfMaskingViews = len(child.MaskingView)
+		case editPayload.MergeStorageGroupParam != nil:
+			for _, maskingViewID := range child.MaskingView {
+				delete(Data.MaskingViewIDToMaskingView, maskingViewID)
+			}
+			child.MaskingView = make([]string, 0)
+			child.NumOfMaskingViews = 0
+			linkChildStorageGroup(parent, child)
+		default:
+			linkChildStorageGroup(parent, child)
+		}
+	}
+}
+
+// volumeInStorageGroups reports whether a volume is in any of the storage groups.
+func volumeInStorageGroups(vol *types.Volume, storageGroupIDs []string) bool {
+	for _, volSG := range vol.StorageGroupIDList {
+		for _, storageGroupID := range storageGroupIDs {
+			if volSG == storageGroupID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// editStorageGroupSettings applies the setting changes of a PUT StorageGroup payload to the cached storage group.
+// Service level and SRP changes return a job, as they do on the array.
+func editStorageGroupSettings(w http.ResponseWriter, sgID string, editPayload *types.EditStorageGroupActionParam) {
+	if editPayload.SetHostIOLimitsParam == nil && editPayload.EditCompressionParam == nil &&
+		editPayload.EditStorageGroupSLOParam == nil && editPayload.EditStorageGroupSRPParam == nil {
+		return
+	}
+	sg, ok := Data.StorageGroupIDToStorageGroup[sgID]
+	if !ok {
+		writeError(w, "StorageGroup not found", http.StatusNotFound)
+		return
+	}
+	if limits := editPayload.SetHostIOLimitsParam; limits != nil {
+		if limits.HostIOLimitMBSec == "NOLIMIT" && limits.HostIOLimitIOSec == "NOLIMIT" {
+			sg.HostIOLimit = nil
+		} else {
+			sg.HostIOLimit = &types.SetHostIOLimitsParam{
+				HostIOLimitMBSec:    limits.HostIOLimitMBSec,
+				HostIOLimitIOSec:    limits.HostIOLimitIOSec,
+				DynamicDistribution: limits.DynamicDistribution,
+			}
+		}
+	}
+	if editPayload.EditCompressionParam != nil && editPayload.EditCompressionParam.Compression != nil {
+		sg.Compression = *editPayload.EditCompressionParam.Compression
+		sg.CompressionRatio = "1.0:1"
+		sg.CompressionRatioToOne = 1
+		if !sg.Compression {
+			sg.CompressionRatio = ""
+			sg.CompressionRatioToOne = 0
+		}
+	}
+	if editPayload.EditStorageGroupSLOParam == nil && editPayload.EditStorageGroupSRPParam == nil {
+		return
+	}
+	if !InducedErrors.JobFailedError {
+		if editPayload.EditStorageGroupSLOParam != nil {
+			sg.SLO = editPayload.EditStorageGroupSLOParam.SLOID
+		}
+		if editPayload.EditStorageGroupSRPParam != nil {
+			sg.SRP = editPayload.EditStorageGroupSRPParam.SRPID
+		}
+	}
+	jobID := strconv.Itoa(time.Now().Nanosecond())
+	resourceLink := fmt.Sprintf("sloprovisioning/system/%s/storagegroup/%s", DefaultSymmetrixID, sgID)
+	if InducedErrors.JobFailedError {
+		newMockJob(jobID, types.JobStatusRunning, types.JobStatusFailed, resourceLink)
+	} else {
+		newMockJob(jobID, types.JobStatusRunning, types.JobStatusSucceeded, resourceLink)
+	}
+	returnJobByID(w, jobID)
+}
+
 func returnStorageGroup(w http.ResponseWriter, sgID string, remote bool) {
 	if sgID != "" {
 		if InducedErrors.GetSGOnRemote && remote {
@@ -0,0 +1,230 @@
+/*
+ Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pmax
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+	log "github.com/sirupsen/logrus"
+)
+
+// PortGroupProtocolNVMeTCP is the port group protocol of NVMe/TCP port groups
+const PortGroupProtocolNVMeTCP = "NVMe_TCP"
+
+// maxNQNLength is the maximum length of an NVMe Qualified Name
+const maxNQNLength = 223
+
+// nqnRegex matches the "nqn.yyyy-mm.reverse-domain:identifier" form, which includes the
+// "nqn.2014-08.org.nvmexpress:uuid:..." form generated by hosts without a configured NQN.
+var nqnRegex = regexp.MustCompile(`^nqn\.\d{4}-\d{2}\.[A-Za-z0-9][A-Za-z0-9.\-]*:.+$`)
+
+// NVMeHostPath is one path from a host NQN to an array port.
+type NVMeHostPath struct {
+	HostNQN     string
+	InitiatorID string
+	DirectorID  string
+	PortID      string
+}
+
+// NVMeHostConnectivity describes how an NVMe host is connected to the array.
+type NVMeHostConnectivity struct {
+	SymmetrixID    string
+	HostID         string
+	HostNQNs       []string
+	MaskingViewIDs []string
+	Paths          []NVMeHostPath
+	// LoggedInInitiators is the number of initiators currently logged in. The array reports login per
+	// initiator rather than per port, so individual paths carry no login state.
+	LoggedInInitiators int
+}
+
+// IsNVMeHostNQN reports whether nqn is a well formed NVMe Qualified Name.
+func IsNVMeHostNQN(nqn string) bool {
+	return len(nqn) <= maxNQNLength && nqnRegex.MatchString(nqn)
+}
+
+// nqnFromInitiatorID returns the NQN within an initiator ID, which may be prefixed with
+// a director and port, or the empty string if the initiator is not an NVMe initiator.
+func nqnFromInitiatorID(initiatorID string) string {
+	if i := strings.Index(initiatorID, "nqn."); i >= 0 && (i == 0 || initiatorID[i-1] == ':') {
+		return initiatorID[i:]
+	}
+	return ""
+}
+
+// normalizeNGUID strips separators and case from an NGUID, so that the array's form
+// matches the dashed form reported by hosts.
+func normalizeNGUID(nguid string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(nguid), "-", ""))
+}
+
+// CreateNVMeHost creates a host from a list of host NQNs (and optional HostFlags).
+// The NQNs are validated and de-duplicated before the host is created.
+func (c *Client) CreateNVMeHost(ctx context.Context, symID string, hostID string, hostNQNs []string, hostFlags *types.HostFlags) (*types.Host, error) {
+	defer c.TimeSpent("CreateNVMeHost", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	if len(hostNQNs) == 0 {
+		return nil, fmt.Errorf("at least one host NQN is required to create NVMe host %s", hostID)
+	}
+	initiatorIDs := make([]string, 0, len(hostNQNs))
+	for _, nqn := range hostNQNs {
+		nqn = strings.TrimSpace(nqn)
+		if !IsNVMeHostNQN(nqn) {
+			return nil, fmt.Errorf("invalid host NQN %q for NVMe host %s", nqn, hostID)
+		}
+		if !stringInSlice(nqn, initiatorIDs) {
+			initiatorIDs = append(initiatorIDs, nqn)
+		}
+	}
+	return c.CreateHost(ctx, symID, hostID, initiatorIDs, hostFlags)
+}
+
+// GetNVMeInitiators returns the IDs of the NVMe initiators on the array. If hostNQN is not empty,
+// only the initiators of that host NQN are returned; if inHost is true, only initiators in a host are returned.
+func (c *Client) GetNVMeInitiators(ctx context.Context, symID string, hostNQN string, inHost bool) (*types.InitiatorList, error) {
+	defer c.TimeSpent("GetNVMeInitiators", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	initList, err := c.GetInitiatorList(ctx, symID, hostNQN, false, inHost)
+	if err != nil {
+		return nil, err
+	}
+	nvmeInitiators := &types.InitiatorList{InitiatorIDs: make([]string, 0)}
+	for _, initiatorID := range initList.InitiatorIDs {
+		nqn := nqnFromInitiatorID(initiatorID)
+		if nqn == "" || (hostNQN != "" && !strings.EqualFold(nqn, hostNQN)) {
+			continue
+		}
+		nvmeInitiators.InitiatorIDs = append(nvmeInitiators.InitiatorIDs, initiatorID)
+	}
+	sort.Strings(nvmeInitiators.InitiatorIDs)
+	return nvmeInitiators, nil
+}
+
+// GetNVMeHostConnectivity returns the NQNs of an NVMe host and the array ports each of them is connected to.
+func (c *Client) GetNVMeHostConnectivity(ctx context.Context, symID string, hostID string) (*NVMeHostConnectivity, error) {
+	defer c.TimeSpent("GetNVMeHostConnectivity", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	host, err := c.GetHostByID(ctx, symID, hostID)
+	if err != nil {
+		return nil, err
+	}
+	connectivity := &NVMeHostConnectivity{
+		SymmetrixID:    symID,
+		HostID:         hostID,
+		HostNQNs:       make([]string, 0),
+		MaskingViewIDs: host.MaskingviewIDs,
+		Paths:          make([]NVMeHostPath, 0),
+	}
+	for _, initiatorID := range host.Initiators {
+		nqn := nqnFromInitiatorID(initiatorID)
+		if nqn != "" && !stringInSlice(nqn, connectivity.HostNQNs) {
+			connectivity.HostNQNs = append(connectivity.HostNQNs, nqn)
+		}
+	}
+	if len(connectivity.HostNQNs) == 0 {
+		return nil, fmt.Errorf("host %s has no NVMe initiators", hostID)
+	}
+	for _, nqn := range connectivity.HostNQNs {
+		initList, err := c.GetNVMeInitiators(ctx, symID, nqn, false)
+		if err != nil {
+			return nil, err
+		}
+		for _, initiatorID := range initList.InitiatorIDs {
+			initiator, err := c.GetInitiatorByID(ctx, symID, initiatorID)
+			if err != nil {
+				return nil, err
+			}
+			if initiator.LoggedIn {
+				connectivity.LoggedInInitiators++
+			}
+			for _, key := range initiator.SymmetrixPortKey {
+				connectivity.Paths = append(connectivity.Paths, NVMeHostPath{
+					HostNQN:     nqn,
+					InitiatorID: initiatorID,
+					DirectorID:  key.DirectorID,
+					PortID:      key.PortID,
+				})
+			}
+		}
+	}
+	return connectivity, nil
+}
+
+// CreateNVMeTCPPortGroup creates an NVMe/TCP port group after checking that every port exists,
+// is listed only once and is an NVMe/TCP endpoint.
+func (c *Client) CreateNVMeTCPPortGroup(ctx context.Context, symID string, portGroupID string, dirPorts []types.PortKey) (*types.PortGroup, error) {
+	defer c.TimeSpent("CreateNVMeTCPPortGroup", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	if len(dirPorts) == 0 {
+		return nil, fmt.Errorf("at least one port is required to create NVMe/TCP port group %s", portGroupID)
+	}
+	seen := make(map[string]bool, len(dirPorts))
+	for _, key := range dirPorts {
+		name := portKeyString(key.DirectorID, key.PortID)
+		if seen[name] {
+			return nil, fmt.Errorf("port %s:%s is listed more than once", key.DirectorID, key.PortID)
+		}
+		seen[name] = true
+		port, err := c.GetPort(ctx, symID, key.DirectorID, key.PortID)
+		if err != nil {
+			return nil, fmt.Errorf("port %s:%s could not be validated: %s", key.DirectorID, key.PortID, err.Error())
+		}
+		if !port.SymmetrixPort.NvmetcpEndpoint && !strings.HasPrefix(port.SymmetrixPort.Identifier, "nqn.") {
+			return nil, fmt.Errorf("port %s:%s is not an NVMe/TCP endpoint", key.DirectorID, key.PortID)
+		}
+		if !strings.EqualFold(port.SymmetrixPort.PortStatus, "ON") {
+			log.Warnf("port %s:%s added to NVMe/TCP port group %s has status %s", key.DirectorID, key.PortID, portGroupID, port.SymmetrixPort.PortStatus)
+		}
+	}
+	return c.CreatePortGroup(ctx, symID, portGroupID, dirPorts, PortGroupProtocolNVMeTCP)
+}
+
+// GetVolumeByNGUID returns the volume with the given NVMe namespace globally unique identifier.
+// The NGUID may be given in the dashed form reported by hosts, or as a /dev/disk/by-id name such as nvme-eui.6000....
+func (c *Client) GetVolumeByNGUID(ctx context.Context, symID string, nguid string) (*types.Volume, error) {
+	defer c.TimeSpent("GetVolumeByNGUID", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(nguid) == "" {
+		return nil, fmt.Errorf("an NGUID is required")
+	}
+	identifier, err := deviceIdentifier(nguid)
+	if err != nil {
+		return nil, err
+	}
+	volume, err := c.findVolumeByIdentifier(ctx, symID, identifier, []string{"nguid"})
+	if err != nil {
+		return nil, err
+	}
+	if volume != nil {
+		return volume, nil
+	}
+	return nil, fmt.Errorf("no volume found with NGUID %s", nguid)
+}
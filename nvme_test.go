@@ -0,0 +1,158 @@
+/*
+ *
+ * Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pmax
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dell/gopowermax/v2/mock"
+	types "github.com/dell/gopowermax/v2/types/v100"
+	"github.com/stretchr/testify/assert"
+)
+
+const mockNVMeHostNQN = "nqn.1988-11.com.dell.mock:00:e6e2d5b871f1403E169D0"
+
+func TestIsNVMeHostNQN(t *testing.T) {
+	assert.True(t, IsNVMeHostNQN(mockNVMeHostNQN))
+	assert.True(t, IsNVMeHostNQN("nqn.2014-08.org.nvmexpress:uuid:4c4c4544-0034-5310-8052-b4c04f4e3732"))
+	assert.False(t, IsNVMeHostNQN("iqn.1993-08.org.centos:01:5ae577b352a0"))
+	assert.False(t, IsNVMeHostNQN("nqn.2014-08.org.nvmexpress"))
+	assert.False(t, IsNVMeHostNQN("nqn.2014-08.org:"+strings.Repeat("a", 220)))
+}
+
+func TestCreateNVMeHost(t *testing.T) {
+	newNQN := "nqn.2014-08.org.nvmexpress:uuid:4c4c4544-0034-5310-8052-b4c04f4e3732"
+	tests := []struct {
+		name    string
+		nqns    []string
+		wantErr string
+	}{
+		{name: "success", nqns: []string{newNQN, " " + newNQN}},
+		{name: "no NQNs", wantErr: "at least one host NQN"},
+		{name: "invalid NQN", nqns: []string{"iqn.1993-08.org.centos:01:5ae577b352a0"}, wantErr: "invalid host NQN"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newMockReplicationClient(t)
+			_, err := mock.AddInitiator(newNQN, newNQN, "OSHostAndRDF", []string{"OR-1C:001"}, "")
+			assert.NoError(t, err)
+			host, err := c.CreateNVMeHost(context.Background(), mock.DefaultSymmetrixID, "nvme-host", tc.nqns, nil)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, []string{newNQN}, host.Initiators)
+			assert.Equal(t, "NVMETCP", host.HostType)
+		})
+	}
+}
+
+func TestGetNVMeInitiators(t *testing.T) {
+	c := newMockReplicationClient(t)
+	initiators, err := c.GetNVMeInitiators(context.Background(), mock.DefaultSymmetrixID, "", false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{mockNVMeHostNQN}, initiators.InitiatorIDs)
+
+	initiators, err = c.GetNVMeInitiators(context.Background(), mock.DefaultSymmetrixID, "nqn.2014-08.org.other:host", false)
+	assert.NoError(t, err)
+	assert.Empty(t, initiators.InitiatorIDs)
+
+	mock.InducedErrors.GetInitiatorError = true
+	_, err = c.GetNVMeInitiators(context.Background(), mock.DefaultSymmetrixID, "", false)
+	assert.ErrorContains(t, err, "induced error")
+}
+
+func TestGetNVMeHostConnectivity(t *testing.T) {
+	c := newMockReplicationClient(t)
+	nqn := "nqn.2014-08.org.nvmexpress:uuid:4c4c4544-0034-5310-8052-b4c04f4e3732"
+	_, err := mock.AddInitiator(nqn, nqn, "OSHostAndRDF", []string{"OR-1C:001"}, "")
+	assert.NoError(t, err)
+	_, err = mock.AddHost("nvme-host", "NVMETCP", []string{nqn})
+	assert.NoError(t, err)
+	mock.Data.InitiatorIDToInitiator[nqn].LoggedIn = true
+
+	connectivity, err := c.GetNVMeHostConnectivity(context.Background(), mock.DefaultSymmetrixID, "nvme-host")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{nqn}, connectivity.HostNQNs)
+	assert.Len(t, connectivity.Paths, 1)
+	assert.Equal(t, "OR-1C", connectivity.Paths[0].DirectorID)
+	assert.Equal(t, nqn, connectivity.Paths[0].InitiatorID)
+	assert.Equal(t, 1, connectivity.LoggedInInitiators)
+
+	_, err = c.GetNVMeHostConnectivity(context.Background(), mock.DefaultSymmetrixID, "CSI-Test-Node-1-ISCSI")
+	assert.ErrorContains(t, err, "has no NVMe initiators")
+
+	_, err = c.GetNVMeHostConnectivity(context.Background(), mock.DefaultSymmetrixID, "missing")
+	assert.Error(t, err)
+}
+
+func TestCreateNVMeTCPPortGroup(t *testing.T) {
+	tests := []struct {
+		name      string
+		nvmePorts bool
+		ports     []types.PortKey
+		wantErr   string
+	}{
+		{name: "success", nvmePorts: true, ports: []types.PortKey{{DirectorID: "OR-1C", PortID: "1"}, {DirectorID: "OR-2C", PortID: "1"}}},
+		{name: "no ports", nvmePorts: true, wantErr: "at least one port"},
+		{name: "duplicate port", nvmePorts: true, ports: []types.PortKey{{DirectorID: "OR-1C", PortID: "1"}, {DirectorID: "or-1c", PortID: "001"}}, wantErr: "listed more than once"},
+		{name: "iSCSI port", ports: []types.PortKey{{DirectorID: "SE-1E", PortID: "4"}}, wantErr: "not an NVMe/TCP endpoint"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newMockReplicationClient(t)
+			mock.Filters.GetNVMePorts = tc.nvmePorts
+			t.Cleanup(func() { mock.Filters.GetNVMePorts = false })
+			pg, err := c.CreateNVMeTCPPortGroup(context.Background(), mock.DefaultSymmetrixID, "nvme-pg", tc.ports)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "nvme-pg", pg.PortGroupID)
+			assert.Len(t, pg.SymmetrixPortKey, len(tc.ports))
+		})
+	}
+}
+
+func TestGetVolumeByNGUID(t *testing.T) {
+	c := newMockReplicationClient(t)
+	assert.NoError(t, mock.AddNewVolume("0A1B2", "nguid-vol", 1, mock.DefaultStorageGroup))
+	assert.NoError(t, mock.AddNewVolume("0A1B3", "other-vol", 1, mock.DefaultStorageGroup))
+
+	volume, err := c.GetVolumeByNGUID(context.Background(), mock.DefaultSymmetrixID, "60000970-0001-9790-0046-53303030A1B2")
+	assert.NoError(t, err)
+	assert.Equal(t, "0A1B2", volume.VolumeID)
+
+	volume, err = c.GetVolumeByNGUID(context.Background(), mock.DefaultSymmetrixID, "/dev/disk/by-id/nvme-eui.6000097000019790004653303030a1b2")
+	assert.NoError(t, err)
+	assert.Equal(t, "0A1B2", volume.VolumeID)
+
+	_, err = c.GetVolumeByNGUID(context.Background(), mock.DefaultSymmetrixID, "nvme-eui.not-an-nguid")
+	assert.ErrorContains(t, err, "is not a volume WWN or NGUID")
+
+	_, err = c.GetVolumeByNGUID(context.Background(), mock.DefaultSymmetrixID, "")
+	assert.ErrorContains(t, err, "an NGUID is required")
+
+	_, err = c.GetVolumeByNGUID(context.Background(), mock.DefaultSymmetrixID, "600009700001979000465330303FFFFF")
+	assert.ErrorContains(t, err, "no volume found")
+}
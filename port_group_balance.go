@@ -0,0 +1,222 @@
+/*
+ Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pmax
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+	log "github.com/sirupsen/logrus"
+)
+
+// PortUtilizationWindow is how far back from the last available sample port utilization is averaged.
+var PortUtilizationWindow = time.Hour
+
+// PortCandidate is a port considered by BuildBalancedPortGroup.
+type PortCandidate struct {
+	types.PortKey
+	Engine     int
+	PortStatus string
+	// Utilization is the average PercentBusy of the port over PortUtilizationWindow.
+	Utilization float64
+	// UtilizationKnown is false if no performance data was available for the port.
+	UtilizationKnown bool
+}
+
+// PortGroupPlan is a proposed set of ports for a port group. Ports and Protocol can be passed to CreatePortGroup.
+type PortGroupPlan struct {
+	SymmetrixID string
+	Protocol    string
+	Ports       []types.PortKey
+	// Selected are the chosen candidates, in the order they were chosen.
+	Selected []PortCandidate
+	// Skipped maps director:port to why the port was not considered.
+	Skipped   map[string]string
+	Directors int
+	Engines   int
+}
+
+// directorEngine returns the engine of a director, e.g. 1 for FA-1D and FA-2D and 2 for FA-3D, or 0 if unknown.
+func directorEngine(directorID string) int {
+	i := strings.Index(directorID, "-")
+	if i < 0 {
+		return 0
+	}
+	digits := ""
+	for _, r := range directorID[i+1:] {
+		if r < '0' || r > '9' {
+			break
+		}
+		digits += string(r)
+	}
+	n, err := strconv.Atoi(digits)
+	if err != nil || n <= 0 {
+		return 0
+	}
+	return (n + 1) / 2
+}
+
+// getPortUtilization returns the average PercentBusy of each port of a director with performance data.
+func (c *Client) getPortUtilization(ctx context.Context, symID string, directorID string) (map[string]float64, error) {
+	keys, err := c.GetFEPortPerfKeys(ctx, symID, directorID)
+	if err != nil {
+		return nil, err
+	}
+	utilization := make(map[string]float64)
+	for _, info := range keys.FEPortInfos {
+		averages, _, err := averageWindowMetrics(info.FirstAvailableDate, info.LastAvailableDate, PortUtilizationWindow,
+			func(start, end int64) ([]types.FEPortMetric, error) {
+				metrics, err := c.GetFEPortMetrics(ctx, symID, directorID, info.PortID, []string{"PercentBusy"}, start, end)
+				if err != nil {
+					return nil, err
+				}
+				return metrics.ResultList.Result, nil
+			},
+			func(metric types.FEPortMetric) float64 { return metric.PercentBusy },
+		)
+		if err != nil {
+			return nil, err
+		}
+		if averages != nil {
+			utilization[portKeyString(directorID, info.PortID)] = averages[0]
+		}
+	}
+	return utilization, nil
+}
+
+// BuildBalancedPortGroup plans a port group of count online ports of the given protocol (as used by
+// CreatePortGroup, e.g. "SCSI_FC" or "iSCSI"). Ports are spread across engines first and directors second,
+// and within those constraints the least utilized ports are preferred, ports without performance data coming after
+// all measured ports. Ports whose status is not ON are skipped.
+func (c *Client) BuildBalancedPortGroup(ctx context.Context, symID string, protocol string, count int) (*PortGroupPlan, error) {
+	defer c.TimeSpent("BuildBalancedPortGroup", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	if count <= 0 {
+		return nil, fmt.Errorf("port count must be positive")
+	}
+	portList, err := c.GetPortListByProtocol(ctx, symID, protocol)
+	if err != nil {
+		return nil, err
+	}
+	plan := &PortGroupPlan{
+		SymmetrixID: symID,
+		Protocol:    protocol,
+		Ports:       make([]types.PortKey, 0, count),
+		Selected:    make([]PortCandidate, 0, count),
+		Skipped:     make(map[string]string),
+	}
+
+	var mu sync.Mutex
+	candidates := make([]PortCandidate, 0, len(portList.SymmetrixPortKey))
+	directors := make([]string, 0)
+	if err := runBounded(ctx, DefaultRequestConcurrency, len(portList.SymmetrixPortKey), func(i int) {
+		key := portList.SymmetrixPortKey[i]
+		port, err := c.GetPort(ctx, symID, key.DirectorID, key.PortID)
+		mu.Lock()
+		defer mu.Unlock()
+		name := key.DirectorID + ":" + key.PortID
+		if err != nil {
+			plan.Skipped[name] = err.Error()
+			return
+		}
+		if !strings.EqualFold(port.SymmetrixPort.PortStatus, "ON") {
+			plan.Skipped[name] = "port status " + port.SymmetrixPort.PortStatus
+			return
+		}
+		candidates = append(candidates, PortCandidate{
+			PortKey:    key,
+			Engine:     directorEngine(key.DirectorID),
+			PortStatus: port.SymmetrixPort.PortStatus,
+		})
+		if !stringInSlice(key.DirectorID, directors) {
+			directors = append(directors, key.DirectorID)
+		}
+	}); err != nil {
+		return nil, err
+	}
+	if len(candidates) < count {
+		return nil, fmt.Errorf("%d %s ports requested but only %d are online", count, protocol, len(candidates))
+	}
+
+	// Utilization is a preference only, so directors without performance data are not an error
+	utilization := make(map[string]float64)
+	if err := runBounded(ctx, DefaultRequestConcurrency, len(directors), func(i int) {
+		directorUtilization, err := c.getPortUtilization(ctx, symID, directors[i])
+		if err != nil {
+			log.Warnf("no port utilization for director %s: %s", directors[i], err.Error())
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for name, busy := range directorUtilization {
+			utilization[name] = busy
+		}
+	}); err != nil {
+		return nil, err
+	}
+	for i := range candidates {
+		busy, ok := utilization[portKeyString(candidates[i].DirectorID, candidates[i].PortID)]
+		candidates[i].Utilization = busy
+		candidates[i].UtilizationKnown = ok
+	}
+
+	perEngine := make(map[int]int)
+	perDirector := make(map[string]int)
+	// better reports whether a is a better next choice than b
+	better := func(a, b PortCandidate) bool {
+		if perEngine[a.Engine] != perEngine[b.Engine] {
+			return perEngine[a.Engine] < perEngine[b.Engine]
+		}
+		if perDirector[a.DirectorID] != perDirector[b.DirectorID] {
+			return perDirector[a.DirectorID] < perDirector[b.DirectorID]
+		}
+		// a port without performance data may be busy, so measured ports come first
+		if a.UtilizationKnown != b.UtilizationKnown {
+			return a.UtilizationKnown
+		}
+		if a.Utilization != b.Utilization {
+			return a.Utilization < b.Utilization
+		}
+		return portKeyString(a.DirectorID, a.PortID) < portKeyString(b.DirectorID, b.PortID)
+	}
+	for len(plan.Selected) < count {
+		best := 0
+		for i := 1; i < len(candidates); i++ {
+			if better(candidates[i], candidates[best]) {
+				best = i
+			}
+		}
+		chosen := candidates[best]
+		candidates = append(candidates[:best], candidates[best+1:]...)
+		if perDirector[chosen.DirectorID] == 0 {
+			plan.Directors++
+		}
+		if perEngine[chosen.Engine] == 0 {
+			plan.Engines++
+		}
+		perEngine[chosen.Engine]++
+		perDirector[chosen.DirectorID]++
+		plan.Selected = append(plan.Selected, chosen)
+		plan.Ports = append(plan.Ports, chosen.PortKey)
+	}
+	return plan, nil
+}
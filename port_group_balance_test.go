@@ -0,0 +1,125 @@
+/*
+ *
+ * Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pmax
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDirectorEngine(t *testing.T) {
+	assert.Equal(t, 1, directorEngine("FA-1D"))
+	assert.Equal(t, 1, directorEngine("FA-2D"))
+	assert.Equal(t, 2, directorEngine("SE-3E"))
+	assert.Equal(t, 8, directorEngine("OR-16C"))
+	assert.Equal(t, 0, directorEngine("bogus"))
+}
+
+// newBalancedPortServer serves six FC ports on engines 1 and 2. FA-4D:4 is offline and
+// FA-3D has no performance data.
+func newBalancedPortServer() *httptest.Server {
+	busy := map[string]float64{"FA-1D:4": 50, "FA-1D:5": 10, "FA-2D:4": 30}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := r.URL.Path
+		var out interface{}
+		switch {
+		case strings.HasSuffix(path, FEPort+Keys):
+			params := &types.FEPortKeysParam{}
+			_ = json.NewDecoder(r.Body).Decode(params)
+			if params.DirectorID == "FA-3D" {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"message":"no performance data"}`))
+				return
+			}
+			result := &types.FEPortKeysResult{}
+			for name := range busy {
+				if strings.HasPrefix(name, params.DirectorID+":") {
+					result.FEPortInfos = append(result.FEPortInfos, types.FEPortInfo{
+						PortID: strings.TrimPrefix(name, params.DirectorID+":"), FirstAvailableDate: 0, LastAvailableDate: 7200000,
+					})
+				}
+			}
+			out = result
+		case strings.HasSuffix(path, FEPort+Metrics):
+			params := &types.FEPortMetricsParam{}
+			_ = json.NewDecoder(r.Body).Decode(params)
+			if params.StartDate != 3600000 {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			value := busy[params.DirectorID+":"+params.PortID]
+			out = &types.FEPortMetricsIterator{ResultList: types.FEPortMetricsResultList{Result: []types.FEPortMetric{
+				{PercentBusy: value - 5}, {PercentBusy: value + 5},
+			}}}
+		case strings.HasSuffix(path, XPort):
+			out = &types.PortList{SymmetrixPortKey: []types.PortKey{
+				{DirectorID: "FA-1D", PortID: "4"}, {DirectorID: "FA-1D", PortID: "5"}, {DirectorID: "FA-2D", PortID: "4"},
+				{DirectorID: "FA-3D", PortID: "4"}, {DirectorID: "FA-3D", PortID: "5"}, {DirectorID: "FA-4D", PortID: "4"},
+			}}
+		default:
+			parts := strings.Split(path, "/")
+			status := "ON"
+			if parts[len(parts)-3] == "FA-4D" {
+				status = "OFF"
+			}
+			out = &types.Port{SymmetrixPort: types.SymmetrixPortType{PortStatus: status}}
+		}
+		_ = json.NewEncoder(w).Encode(out)
+	}))
+}
+
+func TestBuildBalancedPortGroup(t *testing.T) {
+	symID := "000000000001"
+	server := newBalancedPortServer()
+	defer server.Close()
+	c, err := NewClientWithArgs(server.URL, "", true, true, "")
+	assert.NoError(t, err)
+	c.SetAllowedArrays([]string{symID})
+
+	plan, err := c.BuildBalancedPortGroup(context.Background(), symID, "SCSI_FC", 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.PortKey{
+		{DirectorID: "FA-1D", PortID: "5"}, {DirectorID: "FA-3D", PortID: "4"}, {DirectorID: "FA-2D", PortID: "4"},
+	}, plan.Ports)
+	assert.Equal(t, 2, plan.Engines)
+	assert.Equal(t, 3, plan.Directors)
+	assert.True(t, plan.Selected[0].UtilizationKnown)
+	assert.Equal(t, 10.0, plan.Selected[0].Utilization)
+	assert.False(t, plan.Selected[1].UtilizationKnown)
+
+	// a port without performance data, whose utilization reads as 0, does not beat a measured idle port
+	plan, err = c.BuildBalancedPortGroup(context.Background(), symID, "SCSI_FC", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.PortKey{{DirectorID: "FA-1D", PortID: "5"}}, plan.Ports)
+	assert.Equal(t, map[string]string{"FA-4D:4": "port status OFF"}, plan.Skipped)
+
+	_, err = c.BuildBalancedPortGroup(context.Background(), symID, "SCSI_FC", 6)
+	assert.ErrorContains(t, err, "only 5 are online")
+
+	_, err = c.BuildBalancedPortGroup(context.Background(), symID, "SCSI_FC", 0)
+	assert.ErrorContains(t, err, "must be positive")
+}
@@ -27,8 +27,20 @@ import (
 
 // The follow constants are for internal use within the pmax library.
 const (
-	Replication = "replication/"
-	SnapID      = "/snapid"
+	Replication    = "replication/"
+	SnapID         = "/snapid"
+	XCloudProvider = "/cloud_provider"
+	XCloudSnapshot = "/cloud_snapshot"
+)
+
+// Snapshot policy types and cloud retention limits
+const (
+	SnapshotPolicyTypeLocal = "local"
+	SnapshotPolicyTypeCloud = "cloud"
+	// MinCloudRetentionDays is the minimum number of days a cloud snapshot policy can retain snapshots for
+	MinCloudRetentionDays = 3
+	// MaxCloudRetentionDays is the maximum number of days a cloud snapshot policy can retain snapshots for
+	MaxCloudRetentionDays = 5110
 )
 
 // SnapshotAction A list of possible Snapshot actions.
@@ -424,3 +436,119 @@ func (c *Client) GetSnapshotPolicyList(ctx context.Context, symID string) (*type
 	}
 	return snapshotPolicies, nil
 }
+
+// GetSnapshotPolicyStorageGroupList returns the names of the storage groups associated with a snapshot policy
+func (c *Client) GetSnapshotPolicyStorageGroupList(ctx context.Context, symID string, snapshotPolicyID string) (*types.SnapshotPolicyStorageGroupList, error) {
+	defer c.TimeSpent("GetSnapshotPolicyStorageGroupList", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	URL := c.urlPrefix() + Replication + SymmetrixX + symID + SnapshotPolicy + "/" + snapshotPolicyID + XStorageGroup
+	ctx, cancel := c.GetTimeoutContext(ctx)
+	defer cancel()
+	sgList := &types.SnapshotPolicyStorageGroupList{}
+	err := c.api.Get(ctx, URL, c.getDefaultHeaders(), sgList)
+	if err != nil {
+		log.Error("GetSnapshotPolicyStorageGroupList failed: " + err.Error())
+		return nil, err
+	}
+	return sgList, nil
+}
+
+// CreateCloudSnapshotPolicy creates a cloud Snapshot policy and returns a types.SnapshotPolicy.
+// The policy must name a cloud provider known to the array and a retention between MinCloudRetentionDays and MaxCloudRetentionDays.
+func (c *Client) CreateCloudSnapshotPolicy(ctx context.Context, symID string, snapshotPolicyID string, interval string, offsetMins int32,
+	complianceCountWarn int64, complianceCountCritical int64, cloudDetails types.CloudSnapshotPolicyDetails,
+) (*types.SnapshotPolicy, error) {
+	defer c.TimeSpent("CreateCloudSnapshotPolicy", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	if cloudDetails.CloudProviderName == "" {
+		return nil, fmt.Errorf("a cloud provider name is required for cloud snapshot policy %s", snapshotPolicyID)
+	}
+	if err := validateCloudRetentionDays(cloudDetails.CloudRetentionDays); err != nil {
+		return nil, err
+	}
+	providers, err := c.GetCloudProviderList(ctx, symID)
+	if err != nil {
+		return nil, err
+	}
+	if !stringInSlice(cloudDetails.CloudProviderName, providers.CloudProviderIDs) {
+		return nil, fmt.Errorf("cloud provider %s is not configured on Symmetrix %s", cloudDetails.CloudProviderName, symID)
+	}
+	optionalPayload := map[string]interface{}{
+		"cloudSnapshotPolicyDetails": &cloudDetails,
+	}
+	return c.CreateSnapshotPolicy(ctx, symID, snapshotPolicyID, interval, offsetMins, complianceCountWarn, complianceCountCritical, optionalPayload)
+}
+
+// ModifyCloudSnapshotPolicy modifies a cloud Snapshot policy. The snapshot count only applies to local policies and is rejected.
+func (c *Client) ModifyCloudSnapshotPolicy(ctx context.Context, symID string, snapshotPolicyID string, modify types.ModifySnapshotPolicyParam) error {
+	defer c.TimeSpent("ModifyCloudSnapshotPolicy", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return err
+	}
+	if modify.SnapshotCount != 0 {
+		return fmt.Errorf("snapshot count cannot be set on cloud snapshot policy %s", snapshotPolicyID)
+	}
+	if modify.CloudRetentionDays != 0 {
+		if err := validateCloudRetentionDays(modify.CloudRetentionDays); err != nil {
+			return err
+		}
+	}
+	policy, err := c.GetSnapshotPolicy(ctx, symID, snapshotPolicyID)
+	if err != nil {
+		return err
+	}
+	if policy.Type != SnapshotPolicyTypeCloud {
+		return fmt.Errorf("snapshot policy %s is a %s policy, not a cloud policy", snapshotPolicyID, policy.Type)
+	}
+	optionalPayload := map[string]interface{}{
+		"modify": &modify,
+	}
+	return c.UpdateSnapshotPolicy(ctx, symID, "Modify", snapshotPolicyID, optionalPayload)
+}
+
+func validateCloudRetentionDays(days int32) error {
+	if days < MinCloudRetentionDays || days > MaxCloudRetentionDays {
+		return fmt.Errorf("cloud retention days must be between %d and %d, got %d", MinCloudRetentionDays, MaxCloudRetentionDays, days)
+	}
+	return nil
+}
+
+// GetCloudProviderList returns the names of the cloud providers configured on the Symmetrix
+func (c *Client) GetCloudProviderList(ctx context.Context, symID string) (*types.CloudProviderList, error) {
+	defer c.TimeSpent("GetCloudProviderList", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	URL := c.urlPrefix() + Replication + SymmetrixX + symID + XCloudProvider
+	ctx, cancel := c.GetTimeoutContext(ctx)
+	defer cancel()
+	providers := &types.CloudProviderList{}
+	err := c.api.Get(ctx, URL, c.getDefaultHeaders(), providers)
+	if err != nil {
+		log.Error("GetCloudProviderList failed: " + err.Error())
+		return nil, err
+	}
+	return providers, nil
+}
+
+// GetStorageGroupCloudSnapshots returns the snapshots of a storage group which are resident in a cloud provider
+func (c *Client) GetStorageGroupCloudSnapshots(ctx context.Context, symID string, storageGroupID string) (*types.CloudSnapshotList, error) {
+	defer c.TimeSpent("GetStorageGroupCloudSnapshots", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	URL := c.urlPrefix() + Replication + SymmetrixX + symID + XStorageGroup + "/" + storageGroupID + XCloudSnapshot
+	ctx, cancel := c.GetTimeoutContext(ctx)
+	defer cancel()
+	snapshots := &types.CloudSnapshotList{}
+	err := c.api.Get(ctx, URL, c.getDefaultHeaders(), snapshots)
+	if err != nil {
+		log.Error("GetStorageGroupCloudSnapshots failed: " + err.Error())
+		return nil, err
+	}
+	return snapshots, nil
+}
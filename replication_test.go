@@ -0,0 +1,198 @@
+/*
+ *
+ * Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pmax
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dell/gopowermax/v2/mock"
+	types "github.com/dell/gopowermax/v2/types/v100"
+	"github.com/stretchr/testify/assert"
+)
+
+func newMockReplicationClient(t *testing.T) Pmax {
+	mock.Reset()
+	server := httptest.NewServer(mock.GetHandler())
+	t.Cleanup(server.Close)
+	c, err := NewClientWithArgs(server.URL, "", true, true, "")
+	assert.NoError(t, err)
+	c.SetAllowedArrays([]string{mock.DefaultSymmetrixID})
+	return c
+}
+
+func TestCreateCloudSnapshotPolicy(t *testing.T) {
+	tests := []struct {
+		name         string
+		details      types.CloudSnapshotPolicyDetails
+		inducedError string
+		wantErr      string
+	}{
+		{
+			name:    "success",
+			details: types.CloudSnapshotPolicyDetails{CloudProviderName: mock.DefaultCloudProvider, CloudRetentionDays: 30},
+		},
+		{
+			name:    "missing provider",
+			details: types.CloudSnapshotPolicyDetails{CloudRetentionDays: 30},
+			wantErr: "cloud provider name is required",
+		},
+		{
+			name:    "unknown provider",
+			details: types.CloudSnapshotPolicyDetails{CloudProviderName: "nope", CloudRetentionDays: 30},
+			wantErr: "cloud provider nope is not configured",
+		},
+		{
+			name:    "retention too short",
+			details: types.CloudSnapshotPolicyDetails{CloudProviderName: mock.DefaultCloudProvider, CloudRetentionDays: 2},
+			wantErr: "cloud retention days must be between 3 and 5110",
+		},
+		{
+			name:         "provider list error",
+			details:      types.CloudSnapshotPolicyDetails{CloudProviderName: mock.DefaultCloudProvider, CloudRetentionDays: 30},
+			inducedError: "GetCloudProviderListError",
+			wantErr:      "induced error",
+		},
+		{
+			name:         "create error",
+			details:      types.CloudSnapshotPolicyDetails{CloudProviderName: mock.DefaultCloudProvider, CloudRetentionDays: 30},
+			inducedError: "CreateSnapshotPolicyError",
+			wantErr:      "induced error",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newMockReplicationClient(t)
+			switch tc.inducedError {
+			case "GetCloudProviderListError":
+				mock.InducedErrors.GetCloudProviderListError = true
+			case "CreateSnapshotPolicyError":
+				mock.InducedErrors.CreateSnapshotPolicyError = true
+			}
+			policy, err := c.CreateCloudSnapshotPolicy(context.Background(), mock.DefaultSymmetrixID, "CloudDaily", "1 Day", 0, 5, 2, tc.details)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, SnapshotPolicyTypeCloud, policy.Type)
+			assert.Equal(t, mock.DefaultCloudProvider, policy.ProviderName)
+			assert.Equal(t, int64(30), policy.RetentionDays)
+			assert.Equal(t, int64(24*60), policy.IntervalMinutes)
+		})
+	}
+}
+
+func TestModifyCloudSnapshotPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		policyID string
+		modify   types.ModifySnapshotPolicyParam
+		wantErr  string
+	}{
+		{
+			name:     "success",
+			policyID: "CloudDaily",
+			modify:   types.ModifySnapshotPolicyParam{CloudRetentionDays: 60, ComplianceCountWarning: 3},
+		},
+		{
+			name:     "local policy rejected",
+			policyID: "WeeklyDefault",
+			modify:   types.ModifySnapshotPolicyParam{CloudRetentionDays: 60},
+			wantErr:  "not a cloud policy",
+		},
+		{
+			name:     "snapshot count rejected",
+			policyID: "CloudDaily",
+			modify:   types.ModifySnapshotPolicyParam{SnapshotCount: 4},
+			wantErr:  "snapshot count cannot be set",
+		},
+		{
+			name:     "retention too long",
+			policyID: "CloudDaily",
+			modify:   types.ModifySnapshotPolicyParam{CloudRetentionDays: 6000},
+			wantErr:  "cloud retention days must be between",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newMockReplicationClient(t)
+			_, err := c.CreateCloudSnapshotPolicy(context.Background(), mock.DefaultSymmetrixID, "CloudDaily", "1 Day", 0, 5, 2,
+				types.CloudSnapshotPolicyDetails{CloudProviderName: mock.DefaultCloudProvider, CloudRetentionDays: 30})
+			assert.NoError(t, err)
+
+			err = c.ModifyCloudSnapshotPolicy(context.Background(), mock.DefaultSymmetrixID, tc.policyID, tc.modify)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			policy, err := c.GetSnapshotPolicy(context.Background(), mock.DefaultSymmetrixID, tc.policyID)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(60), policy.RetentionDays)
+			assert.Equal(t, int64(3), policy.ComplianceCountWarning)
+		})
+	}
+}
+
+func TestGetCloudProviderList(t *testing.T) {
+	c := newMockReplicationClient(t)
+	providers, err := c.GetCloudProviderList(context.Background(), mock.DefaultSymmetrixID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{mock.DefaultCloudProvider}, providers.CloudProviderIDs)
+
+	mock.InducedErrors.GetCloudProviderListError = true
+	_, err = c.GetCloudProviderList(context.Background(), mock.DefaultSymmetrixID)
+	assert.ErrorContains(t, err, "induced error")
+
+	_, err = c.GetCloudProviderList(context.Background(), "000000000000")
+	assert.Error(t, err)
+}
+
+func TestGetStorageGroupCloudSnapshots(t *testing.T) {
+	tests := []struct {
+		name         string
+		sgID         string
+		inducedError bool
+		wantCount    int
+		wantErr      string
+	}{
+		{name: "with cloud snapshots", sgID: mock.DefaultStorageGroup, wantCount: 1},
+		{name: "without cloud snapshots", sgID: mock.DefaultStorageGroup1, wantCount: 0},
+		{name: "unknown storage group", sgID: "missing", wantErr: "not found"},
+		{name: "induced error", sgID: mock.DefaultStorageGroup, inducedError: true, wantErr: "induced error"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newMockReplicationClient(t)
+			mock.InducedErrors.GetCloudSnapshotListError = tc.inducedError
+			snapshots, err := c.GetStorageGroupCloudSnapshots(context.Background(), mock.DefaultSymmetrixID, tc.sgID)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, snapshots.CloudSnapshots, tc.wantCount)
+			for _, snap := range snapshots.CloudSnapshots {
+				assert.Equal(t, mock.DefaultCloudProvider, snap.CloudProviderName)
+			}
+		})
+	}
+}
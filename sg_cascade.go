@@ -0,0 +1,222 @@
+/*
+ Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pmax
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+	log "github.com/sirupsen/logrus"
+)
+
+// StorageGroupTree is a storage group with its volumes and child storage groups.
+type StorageGroupTree struct {
+	StorageGroup *types.StorageGroup
+	// VolumeIDs are the volumes of the storage group; for a parent these include the volumes of its children.
+	VolumeIDs []string
+	Children  []*StorageGroupTree
+}
+
+// hasServiceLevel reports whether a storage group has a service level set.
+func hasServiceLevel(sg *types.StorageGroup) bool {
+	return storageGroupServiceLevel(sg) != "None"
+}
+
+// validateCascade checks that child can be a child storage group of parent. Cascading is one level deep,
+// a child has a single parent, and a service level may be set on the parent or the children but not both.
+func validateCascade(parent, child *types.StorageGroup) error {
+	if parent.StorageGroupID == child.StorageGroupID {
+		return fmt.Errorf("storage group %s cannot be its own child", parent.StorageGroupID)
+	}
+	if parent.NumOfParentSGs > 0 || len(parent.ParentStorageGroup) > 0 {
+		return fmt.Errorf("storage group %s is a child storage group and cannot have children", parent.StorageGroupID)
+	}
+	if child.NumOfChildSGs > 0 || len(child.ChildStorageGroup) > 0 {
+		return fmt.Errorf("storage group %s is a parent storage group and cannot be a child", child.StorageGroupID)
+	}
+	if child.NumOfParentSGs > 0 || len(child.ParentStorageGroup) > 0 {
+		return fmt.Errorf("storage group %s is already a child of %s and cannot have another parent",
+			child.StorageGroupID, strings.Join(child.ParentStorageGroup, ", "))
+	}
+	if hasServiceLevel(parent) && hasServiceLevel(child) {
+		return fmt.Errorf("service level %s is set on parent storage group %s and %s on child %s; only one may have a service level",
+			storageGroupServiceLevel(parent), parent.StorageGroupID, storageGroupServiceLevel(child), child.StorageGroupID)
+	}
+	return nil
+}
+
+// getCascadePair returns a parent and child storage group.
+func (c *Client) getCascadePair(ctx context.Context, symID string, parentID string, childID string) (*types.StorageGroup, *types.StorageGroup, error) {
+	parent, err := c.GetStorageGroup(ctx, symID, parentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	child, err := c.GetStorageGroup(ctx, symID, childID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return parent, child, nil
+}
+
+// updateCascade sends a storage group update to the parent and returns the updated parent.
+func (c *Client) updateCascade(ctx context.Context, symID string, parentID string, edit types.EditStorageGroupActionParam) (*types.StorageGroup, error) {
+	payload := &types.UpdateStorageGroupPayload{
+		EditStorageGroupActionParam: edit,
+		ExecutionOption:             types.ExecutionOptionSynchronous,
+	}
+	ifDebugLogPayload(payload)
+	if err := c.UpdateStorageGroupS(ctx, symID, parentID, payload); err != nil {
+		return nil, err
+	}
+	return c.GetStorageGroup(ctx, symID, parentID)
+}
+
+// AddChildStorageGroup adds an existing storage group as a child of parentID and returns the parent.
+// Nothing is changed if it is already a child.
+func (c *Client) AddChildStorageGroup(ctx context.Context, symID string, parentID string, childID string) (*types.StorageGroup, error) {
+	defer c.TimeSpent("AddChildStorageGroup", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	parent, child, err := c.getCascadePair(ctx, symID, parentID, childID)
+	if err != nil {
+		return nil, err
+	}
+	if stringInSlice(childID, parent.ChildStorageGroup) {
+		log.Debugf("storage group %s is already a child of %s", childID, parentID)
+		return parent, nil
+	}
+	if err = validateCascade(parent, child); err != nil {
+		log.Error("AddChildStorageGroup failed: " + err.Error())
+		return nil, err
+	}
+	return c.updateCascade(ctx, symID, parentID, types.EditStorageGroupActionParam{
+		ExpandStorageGroupParam: &types.ExpandStorageGroupParam{
+			AddExistingStorageGroupParam: &types.AddExistingStorageGroupParam{
+				StorageGroupIDs: []string{childID},
+			},
+		},
+	})
+}
+
+// RemoveChildStorageGroup removes a child storage group from parentID and returns the parent. The child
+// storage group is not deleted. force is needed if the parent is in a masking view.
+func (c *Client) RemoveChildStorageGroup(ctx context.Context, symID string, parentID string, childID string, force bool) (*types.StorageGroup, error) {
+	defer c.TimeSpent("RemoveChildStorageGroup", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	parent, err := c.GetStorageGroup(ctx, symID, parentID)
+	if err != nil {
+		return nil, err
+	}
+	if !stringInSlice(childID, parent.ChildStorageGroup) {
+		return nil, fmt.Errorf("storage group %s is not a child of %s", childID, parentID)
+	}
+	return c.updateCascade(ctx, symID, parentID, types.EditStorageGroupActionParam{
+		RemoveStorageGroupParam: &types.RemoveStorageGroupParam{
+			StorageGroupIDs: []string{childID},
+			Force:           force,
+		},
+	})
+}
+
+// MergeStorageGroups merges the masked storage group storageGroupID into the masking view of parentID as
+// a child, and returns the parent. The masking view of storageGroupID is removed.
+func (c *Client) MergeStorageGroups(ctx context.Context, symID string, parentID string, storageGroupID string) (*types.StorageGroup, error) {
+	defer c.TimeSpent("MergeStorageGroups", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	parent, child, err := c.getCascadePair(ctx, symID, parentID, storageGroupID)
+	if err != nil {
+		return nil, err
+	}
+	if err = validateCascade(parent, child); err != nil {
+		log.Error("MergeStorageGroups failed: " + err.Error())
+		return nil, err
+	}
+	return c.updateCascade(ctx, symID, parentID, types.EditStorageGroupActionParam{
+		MergeStorageGroupParam: &types.MergeStorageGroupParam{
+			StorageGroupID: storageGroupID,
+		},
+	})
+}
+
+// SplitChildStorageGroup splits a child storage group out of the masking view of parentID into a new
+// masking view maskingViewID, with the same host and port group, and returns the parent.
+func (c *Client) SplitChildStorageGroup(ctx context.Context, symID string, parentID string, childID string, maskingViewID string) (*types.StorageGroup, error) {
+	defer c.TimeSpent("SplitChildStorageGroup", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	if maskingViewID == "" {
+		return nil, fmt.Errorf("a masking view name is required to split storage group %s", childID)
+	}
+	parent, err := c.GetStorageGroup(ctx, symID, parentID)
+	if err != nil {
+		return nil, err
+	}
+	if !stringInSlice(childID, parent.ChildStorageGroup) {
+		return nil, fmt.Errorf("storage group %s is not a child of %s", childID, parentID)
+	}
+	return c.updateCascade(ctx, symID, parentID, types.EditStorageGroupActionParam{
+		SplitChildStorageGroupParam: &types.SplitChildStorageGroupParam{
+			StorageGroupID: childID,
+			MaskingViewID:  maskingViewID,
+		},
+	})
+}
+
+// getStorageGroupTree resolves a storage group and its children, skipping storage groups already in visited.
+func (c *Client) getStorageGroupTree(ctx context.Context, symID string, storageGroupID string, visited map[string]bool) (*StorageGroupTree, error) {
+	visited[storageGroupID] = true
+	sg, err := c.GetStorageGroup(ctx, symID, storageGroupID)
+	if err != nil {
+		return nil, err
+	}
+	volumeIDs, err := c.GetVolumeIDListInStorageGroup(ctx, symID, storageGroupID)
+	if err != nil {
+		return nil, err
+	}
+	tree := &StorageGroupTree{
+		StorageGroup: sg,
+		VolumeIDs:    volumeIDs,
+		Children:     make([]*StorageGroupTree, 0, len(sg.ChildStorageGroup)),
+	}
+	for _, childID := range sg.ChildStorageGroup {
+		if visited[childID] {
+			continue
+		}
+		child, err := c.getStorageGroupTree(ctx, symID, childID, visited)
+		if err != nil {
+			return nil, err
+		}
+		tree.Children = append(tree.Children, child)
+	}
+	return tree, nil
+}
+
+// GetStorageGroupTree returns a storage group with its volumes and, recursively, its child storage groups.
+func (c *Client) GetStorageGroupTree(ctx context.Context, symID string, storageGroupID string) (*StorageGroupTree, error) {
+	defer c.TimeSpent("GetStorageGroupTree", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	return c.getStorageGroupTree(ctx, symID, storageGroupID, make(map[string]bool))
+}
@@ -0,0 +1,113 @@
+/*
+ *
+ * Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pmax
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dell/gopowermax/v2/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCascadedStorageGroups(t *testing.T) {
+	c := newMockReplicationClient(t)
+	ctx := context.Background()
+	symID := mock.DefaultSymmetrixID
+	for sgID, serviceLevel := range map[string]string{"cascade-parent": "Diamond", "cascade-child": "", "cascade-gold": "Gold", "cascade-masked": "", "cascade-other": ""} {
+		_, err := mock.AddStorageGroup(sgID, "SRP_1", serviceLevel)
+		assert.NoError(t, err)
+	}
+	_, err := mock.AddMaskingView("cascade-mv", "cascade-masked", "CSI-Test-Node-1-ISCSI", "iscsi_ports")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.AddNewVolume("0C001", "cascade-vol-1", 1, "cascade-child"))
+	assert.NoError(t, mock.AddNewVolume("0C002", "cascade-vol-2", 1, "cascade-masked"))
+
+	parent, err := c.AddChildStorageGroup(ctx, symID, "cascade-parent", "cascade-child")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cascade-child"}, parent.ChildStorageGroup)
+	assert.Equal(t, 1, parent.NumOfChildSGs)
+	parent, err = c.AddChildStorageGroup(ctx, symID, "cascade-parent", "cascade-child")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cascade-child"}, parent.ChildStorageGroup)
+
+	tests := []struct {
+		name     string
+		parentID string
+		childID  string
+		wantErr  string
+	}{
+		{name: "service level on both", parentID: "cascade-parent", childID: "cascade-gold", wantErr: "only one may have a service level"},
+		{name: "own child", parentID: "cascade-gold", childID: "cascade-gold", wantErr: "its own child"},
+		{name: "parent as child", parentID: "cascade-masked", childID: "cascade-parent", wantErr: "cannot be a child"},
+		{name: "child of another parent", parentID: "cascade-other", childID: "cascade-child", wantErr: "already a child of cascade-parent"},
+		{name: "child as parent", parentID: "cascade-child", childID: "cascade-masked", wantErr: "cannot have children"},
+		{name: "missing child", parentID: "cascade-parent", childID: "cascade-none", wantErr: "not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := c.AddChildStorageGroup(ctx, symID, tt.parentID, tt.childID)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+	_, err = c.MergeStorageGroups(ctx, symID, "cascade-parent", "cascade-gold")
+	assert.ErrorContains(t, err, "only one may have a service level")
+
+	parent, err = c.MergeStorageGroups(ctx, symID, "cascade-parent", "cascade-masked")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cascade-child", "cascade-masked"}, parent.ChildStorageGroup)
+	_, err = c.GetMaskingViewByID(ctx, symID, "cascade-mv")
+	assert.Error(t, err)
+
+	tree, err := c.GetStorageGroupTree(ctx, symID, "cascade-parent")
+	assert.NoError(t, err)
+	assert.Equal(t, "cascade-parent", tree.StorageGroup.StorageGroupID)
+	assert.ElementsMatch(t, []string{"0C001", "0C002"}, tree.VolumeIDs)
+	if assert.Len(t, tree.Children, 2) {
+		assert.Equal(t, "cascade-child", tree.Children[0].StorageGroup.StorageGroupID)
+		assert.Equal(t, []string{"0C001"}, tree.Children[0].VolumeIDs)
+		assert.Empty(t, tree.Children[0].Children)
+	}
+
+	_, err = c.SplitChildStorageGroup(ctx, symID, "cascade-parent", "cascade-masked", "")
+	assert.Error(t, err)
+	parent, err = c.SplitChildStorageGroup(ctx, symID, "cascade-parent", "cascade-masked", "cascade-split-mv")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cascade-child"}, parent.ChildStorageGroup)
+	maskingView, err := c.GetMaskingViewByID(ctx, symID, "cascade-split-mv")
+	assert.NoError(t, err)
+	assert.Equal(t, "cascade-masked", maskingView.StorageGroupID)
+
+	parent, err = c.RemoveChildStorageGroup(ctx, symID, "cascade-parent", "cascade-child", false)
+	assert.NoError(t, err)
+	assert.Empty(t, parent.ChildStorageGroup)
+	assert.Equal(t, 0, parent.NumOfChildSGs)
+	child, err := c.GetStorageGroup(ctx, symID, "cascade-child")
+	assert.NoError(t, err)
+	assert.Empty(t, child.ParentStorageGroup)
+	_, err = c.RemoveChildStorageGroup(ctx, symID, "cascade-parent", "cascade-child", false)
+	assert.ErrorContains(t, err, "not a child")
+	_, err = c.SplitChildStorageGroup(ctx, symID, "cascade-parent", "cascade-child", "cascade-mv-2")
+	assert.ErrorContains(t, err, "not a child")
+
+	_, err = c.GetStorageGroupTree(ctx, symID, "cascade-none")
+	assert.Error(t, err)
+	_, err = c.AddChildStorageGroup(ctx, "000000000002", "cascade-parent", "cascade-child")
+	assert.Error(t, err)
+}
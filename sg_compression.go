@@ -0,0 +1,216 @@
+/*
+ Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pmax
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+	log "github.com/sirupsen/logrus"
+)
+
+// CompressionCandidate is an uncompressed storage group with an estimate of what compressing it would save.
+type CompressionCandidate struct {
+	SymmetrixID    string
+	StorageGroupID string
+	SRP            string
+	ServiceLevel   string
+	SubscribedGB   float64
+	// AllocatedGB is the capacity used by the volumes, or SubscribedGB if volume allocations could not be read.
+	AllocatedGB       float64
+	UnreducibleDataGB float64
+	// ExpectedRatioToOne is the data reduction ratio achieved by the SRP, zero if the SRP reports none.
+	ExpectedRatioToOne float64
+	// EstimatedSavingsGB is the reducible allocated capacity reduced at ExpectedRatioToOne.
+	EstimatedSavingsGB float64
+}
+
+// CompressionReport lists the uncompressed storage groups of one or more arrays, largest estimated savings first.
+type CompressionReport struct {
+	SymmetrixIDs []string
+	// Compressed and Uncompressed count the storage groups examined; parents and storage groups without an SRP are skipped.
+	Compressed         int
+	Uncompressed       int
+	Candidates         []CompressionCandidate
+	EstimatedSavingsGB float64
+	// Errors maps arrays, or "array/storage group", which could not be read to the error.
+	Errors map[string]string
+}
+
+// SetStorageGroupCompression enables or disables compression of a storage group and returns it. Nothing is
+// changed if compression is already as requested. The storage group must be in an SRP.
+func (c *Client) SetStorageGroupCompression(ctx context.Context, symID string, storageGroupID string, enabled bool) (*types.StorageGroup, error) {
+	defer c.TimeSpent("SetStorageGroupCompression", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	sg, err := c.GetStorageGroup(ctx, symID, storageGroupID)
+	if err != nil {
+		return nil, err
+	}
+	if sg.Compression == enabled {
+		log.Debugf("compression of storage group %s is already %t", storageGroupID, enabled)
+		return sg, nil
+	}
+	if sg.SRP == "" || sg.SRP == "None" {
+		return nil, fmt.Errorf("storage group %s has no SRP so compression cannot be changed", storageGroupID)
+	}
+	payload := &types.UpdateStorageGroupPayload{
+		EditStorageGroupActionParam: types.EditStorageGroupActionParam{
+			EditCompressionParam: &types.EditCompressionParam{Compression: &enabled},
+		},
+		ExecutionOption: types.ExecutionOptionSynchronous,
+	}
+	ifDebugLogPayload(payload)
+	if err = c.UpdateStorageGroupS(ctx, symID, storageGroupID, payload); err != nil {
+		return nil, err
+	}
+	return c.GetStorageGroup(ctx, symID, storageGroupID)
+}
+
+// getCompressionCandidates examines the storage groups of one array for GetCompressionReport.
+func (c *Client) getCompressionCandidates(ctx context.Context, symID string, report *CompressionReport, mu *sync.Mutex) error {
+	sgIDList, err := c.GetStorageGroupIDList(ctx, symID, "", false)
+	if err != nil {
+		return err
+	}
+	storageGroups := make([]*types.StorageGroup, 0, len(sgIDList.StorageGroupIDs))
+	var sgMu sync.Mutex
+	if err := runBounded(ctx, DefaultRequestConcurrency, len(sgIDList.StorageGroupIDs), func(i int) {
+		sg, err := c.GetStorageGroup(ctx, symID, sgIDList.StorageGroupIDs[i])
+		if err != nil {
+			mu.Lock()
+			report.Errors[symID+"/"+sgIDList.StorageGroupIDs[i]] = err.Error()
+			mu.Unlock()
+			return
+		}
+		sgMu.Lock()
+		storageGroups = append(storageGroups, sg)
+		sgMu.Unlock()
+	}); err != nil {
+		return err
+	}
+
+	allocated, err := c.getStorageGroupAllocations(ctx, symID)
+	if err != nil {
+		log.Warnf("no volume allocations for %s, using subscribed capacity: %s", symID, err.Error())
+		allocated = nil
+	}
+	ratios := make(map[string]float64)
+	candidates := make([]CompressionCandidate, 0)
+	compressed := 0
+	for _, sg := range storageGroups {
+		if sg.NumOfChildSGs > 0 || sg.SRP == "" || sg.SRP == "None" {
+			continue
+		}
+		if sg.Compression {
+			compressed++
+			continue
+		}
+		ratio, ok := ratios[sg.SRP]
+		if !ok {
+			srp, err := c.GetStoragePool(ctx, symID, sg.SRP)
+			if err != nil {
+				log.Warnf("no data reduction ratio for SRP %s: %s", sg.SRP, err.Error())
+			} else if srp.SrpEfficiency != nil {
+				ratio = float64(srp.SrpEfficiency.DataReductionRatioToOne)
+			}
+			ratios[sg.SRP] = ratio
+		}
+		candidate := CompressionCandidate{
+			SymmetrixID:        symID,
+			StorageGroupID:     sg.StorageGroupID,
+			SRP:                sg.SRP,
+			ServiceLevel:       storageGroupServiceLevel(sg),
+			SubscribedGB:       sg.CapacityGB,
+			AllocatedGB:        sg.CapacityGB,
+			UnreducibleDataGB:  sg.UnreducibleDataGB,
+			ExpectedRatioToOne: ratio,
+		}
+		if allocated != nil {
+			candidate.AllocatedGB = allocated[sg.StorageGroupID]
+		}
+		if reducible := candidate.AllocatedGB - candidate.UnreducibleDataGB; ratio > 1 && reducible > 0 {
+			candidate.EstimatedSavingsGB = reducible * (1 - 1/ratio)
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	report.Compressed += compressed
+	report.Uncompressed += len(candidates)
+	report.Candidates = append(report.Candidates, candidates...)
+	return nil
+}
+
+// GetCompressionReport returns the uncompressed storage groups of the given arrays, or of all the allowed arrays
+// if none are given, with the capacity compressing them is estimated to save at the data reduction ratio their
+// SRP achieves. Arrays which cannot be read are recorded in Errors.
+func (c *Client) GetCompressionReport(ctx context.Context, symIDs []string) (*CompressionReport, error) {
+	defer c.TimeSpent("GetCompressionReport", time.Now())
+	if len(symIDs) == 0 {
+		symIDList, err := c.GetSymmetrixIDList(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, symID := range symIDList.SymmetrixIDs {
+			if allowed, _ := c.IsAllowedArray(symID); allowed {
+				symIDs = append(symIDs, symID)
+			}
+		}
+	}
+	for _, symID := range symIDs {
+		if _, err := c.IsAllowedArray(symID); err != nil {
+			return nil, err
+		}
+	}
+	report := &CompressionReport{
+		SymmetrixIDs: symIDs,
+		Candidates:   make([]CompressionCandidate, 0),
+		Errors:       make(map[string]string),
+	}
+	// arrays are read one after another, so the storage groups of each are read with the whole
+	// DefaultRequestConcurrency budget
+	var mu sync.Mutex
+	for _, symID := range symIDs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := c.getCompressionCandidates(ctx, symID, report, &mu); err != nil {
+			mu.Lock()
+			report.Errors[symID] = err.Error()
+			mu.Unlock()
+		}
+	}
+	sort.Slice(report.Candidates, func(i, j int) bool {
+		a, b := report.Candidates[i], report.Candidates[j]
+		if a.EstimatedSavingsGB != b.EstimatedSavingsGB {
+			return a.EstimatedSavingsGB > b.EstimatedSavingsGB
+		}
+		if a.SymmetrixID != b.SymmetrixID {
+			return a.SymmetrixID < b.SymmetrixID
+		}
+		return a.StorageGroupID < b.StorageGroupID
+	})
+	for _, candidate := range report.Candidates {
+		report.EstimatedSavingsGB += candidate.EstimatedSavingsGB
+	}
+	return report, nil
+}
@@ -0,0 +1,106 @@
+/*
+ *
+ * Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pmax
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dell/gopowermax/v2/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetStorageGroupCompression(t *testing.T) {
+	c := newMockReplicationClient(t)
+	ctx := context.Background()
+	symID := mock.DefaultSymmetrixID
+
+	sg, err := c.SetStorageGroupCompression(ctx, symID, "CSI-Test-SG-2", true)
+	assert.NoError(t, err)
+	assert.True(t, sg.Compression)
+	sg, err = c.SetStorageGroupCompression(ctx, symID, "CSI-Test-SG-2", true)
+	assert.NoError(t, err)
+	assert.True(t, sg.Compression)
+	sg, err = c.SetStorageGroupCompression(ctx, symID, "CSI-Test-SG-2", false)
+	assert.NoError(t, err)
+	assert.False(t, sg.Compression)
+
+	_, err = c.SetStorageGroupCompression(ctx, symID, "CSI-Test-SG-6", true)
+	assert.ErrorContains(t, err, "no SRP")
+	mock.InducedErrors.UpdateStorageGroupError = true
+	_, err = c.SetStorageGroupCompression(ctx, symID, "CSI-Test-SG-2", true)
+	assert.Error(t, err)
+	mock.InducedErrors.UpdateStorageGroupError = false
+	_, err = c.SetStorageGroupCompression(ctx, symID, "CSI-Test-SG-none", true)
+	assert.Error(t, err)
+	_, err = c.SetStorageGroupCompression(ctx, "000000000002", "CSI-Test-SG-2", true)
+	assert.Error(t, err)
+}
+
+func TestGetCompressionReport(t *testing.T) {
+	c := newMockReplicationClient(t)
+	ctx := context.Background()
+	symID := mock.DefaultSymmetrixID
+
+	report, err := c.GetCompressionReport(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{symID}, report.SymmetrixIDs)
+	assert.Empty(t, report.Errors)
+	assert.Equal(t, 0, report.Compressed)
+	assert.Equal(t, len(report.Candidates), report.Uncompressed)
+	// the mock reports 25 GB allocated in CSI-Test-SG-1 and a data reduction ratio of 2:1
+	top := report.Candidates[0]
+	assert.Equal(t, mock.DefaultStorageGroup, top.StorageGroupID)
+	assert.Equal(t, "SRP_1", top.SRP)
+	assert.Equal(t, "Diamond", top.ServiceLevel)
+	assert.Equal(t, 25.0, top.AllocatedGB)
+	assert.Equal(t, 2.0, top.ExpectedRatioToOne)
+	assert.Equal(t, 12.5, top.EstimatedSavingsGB)
+	assert.Equal(t, 12.5, report.EstimatedSavingsGB)
+	for _, candidate := range report.Candidates {
+		assert.NotEqual(t, "CSI-Test-SG-6", candidate.StorageGroupID)
+	}
+	uncompressed := report.Uncompressed
+
+	_, err = c.SetStorageGroupCompression(ctx, symID, mock.DefaultStorageGroup, true)
+	assert.NoError(t, err)
+	report, err = c.GetCompressionReport(ctx, []string{symID})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Compressed)
+	assert.Equal(t, uncompressed-1, report.Uncompressed)
+	assert.Zero(t, report.EstimatedSavingsGB)
+
+	// without volume allocations the subscribed capacity is used
+	mock.InducedErrors.GetVolumesCapacityBulkError = true
+	report, err = c.GetCompressionReport(ctx, []string{symID})
+	assert.NoError(t, err)
+	assert.Equal(t, report.Candidates[0].SubscribedGB, report.Candidates[0].AllocatedGB)
+	assert.Equal(t, report.Candidates[0].SubscribedGB/2, report.Candidates[0].EstimatedSavingsGB)
+	mock.InducedErrors.GetVolumesCapacityBulkError = false
+
+	mock.InducedErrors.GetStorageGroupError = true
+	report, err = c.GetCompressionReport(ctx, []string{symID})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, report.Errors)
+	assert.Empty(t, report.Candidates)
+	mock.InducedErrors.GetStorageGroupError = false
+
+	_, err = c.GetCompressionReport(ctx, []string{"000000000002"})
+	assert.Error(t, err)
+}
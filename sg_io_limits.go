@@ -0,0 +1,271 @@
+/*
+ Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pmax
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+	log "github.com/sirupsen/logrus"
+)
+
+// Dynamic distribution modes of host I/O limits across the directors of a port group
+const (
+	HostIOLimitDistributionNever     = "Never"
+	HostIOLimitDistributionAlways    = "Always"
+	HostIOLimitDistributionOnFailure = "OnFailure"
+)
+
+// hostIOLimitNone is the host I/O limit value of no limit.
+const hostIOLimitNone = "NOLIMIT"
+
+var (
+	// IOLimitMetricsWindow is how far back from the last available sample storage group throughput is examined.
+	IOLimitMetricsWindow = time.Hour
+	// DefaultIOLimitThreshold is the percentage of a limit at which GetStorageGroupIOLimitReport flags a storage group
+	// when no threshold is given.
+	DefaultIOLimitThreshold = 95.0
+)
+
+// StorageGroupIOLimitUsage is the observed throughput of a storage group against its host I/O limits.
+type StorageGroupIOLimitUsage struct {
+	StorageGroupID string
+	// MaxMBps and MaxIOPS are the limits, zero if not limited.
+	MaxMBps      int
+	MaxIOPS      int
+	Distribution string
+	// MetricsKnown is false if no performance data was available, in which case the throughput figures are zero.
+	MetricsKnown bool
+	AverageMBps  float64
+	PeakMBps     float64
+	AverageIOPS  float64
+	PeakIOPS     float64
+	// MBpsPercent and IOPSPercent are the peaks as a percentage of the limits, zero if not limited.
+	MBpsPercent float64
+	IOPSPercent float64
+	// AtLimit is set if either peak reached the report threshold of its limit.
+	AtLimit bool
+}
+
+// StorageGroupIOLimitReport lists the storage groups of an array which have host I/O limits.
+type StorageGroupIOLimitReport struct {
+	SymmetrixID   string
+	Threshold     float64
+	StorageGroups []StorageGroupIOLimitUsage
+	// AtLimit is the number of storage groups with AtLimit set.
+	AtLimit int
+	// Errors maps storage groups which could not be read to the error.
+	Errors map[string]string
+}
+
+// hostIOLimitValue converts a limit to its payload value, "NOLIMIT" for zero.
+func hostIOLimitValue(limit int) string {
+	if limit == 0 {
+		return hostIOLimitNone
+	}
+	return strconv.Itoa(limit)
+}
+
+// parseHostIOLimit converts a reported limit to a number, zero for no limit.
+func parseHostIOLimit(limit string) int {
+	value, err := strconv.Atoi(limit)
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
+// validateHostIOLimits checks limits against the ranges the array accepts.
+func validateHostIOLimits(maxMBps, maxIOPS int, distribution string) error {
+	if maxMBps < 0 || maxIOPS < 0 {
+		return fmt.Errorf("host I/O limits must not be negative")
+	}
+	if maxMBps == 0 && maxIOPS == 0 {
+		return fmt.Errorf("at least one of the MB/s and IO/s host I/O limits is required")
+	}
+	if maxIOPS != 0 && (maxIOPS < 100 || maxIOPS%100 != 0) {
+		return fmt.Errorf("IO/s host I/O limit %d must be a multiple of 100", maxIOPS)
+	}
+	if !stringInSlice(distribution, []string{HostIOLimitDistributionNever, HostIOLimitDistributionAlways, HostIOLimitDistributionOnFailure}) {
+		return fmt.Errorf("invalid host I/O limit dynamic distribution %q", distribution)
+	}
+	return nil
+}
+
+// updateStorageGroupIOLimits sends a host I/O limits update and returns the updated storage group.
+func (c *Client) updateStorageGroupIOLimits(ctx context.Context, symID string, storageGroupID string, limits *types.SetHostIOLimitsParam) (*types.StorageGroup, error) {
+	payload := &types.UpdateStorageGroupPayload{
+		EditStorageGroupActionParam: types.EditStorageGroupActionParam{
+			SetHostIOLimitsParam: limits,
+		},
+		ExecutionOption: types.ExecutionOptionSynchronous,
+	}
+	ifDebugLogPayload(payload)
+	if err := c.UpdateStorageGroupS(ctx, symID, storageGroupID, payload); err != nil {
+		return nil, err
+	}
+	return c.GetStorageGroup(ctx, symID, storageGroupID)
+}
+
+// SetStorageGroupIOLimits sets or changes the host I/O limits of a storage group. A limit of zero removes that limit,
+// but at least one must be set; IO/s limits must be multiples of 100. distribution is one of the
+// HostIOLimitDistribution modes, HostIOLimitDistributionNever if empty.
+func (c *Client) SetStorageGroupIOLimits(ctx context.Context, symID string, storageGroupID string, maxMBps, maxIOPS int, distribution string) (*types.StorageGroup, error) {
+	defer c.TimeSpent("SetStorageGroupIOLimits", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	if distribution == "" {
+		distribution = HostIOLimitDistributionNever
+	}
+	if err := validateHostIOLimits(maxMBps, maxIOPS, distribution); err != nil {
+		return nil, err
+	}
+	return c.updateStorageGroupIOLimits(ctx, symID, storageGroupID, &types.SetHostIOLimitsParam{
+		HostIOLimitMBSec:    hostIOLimitValue(maxMBps),
+		HostIOLimitIOSec:    hostIOLimitValue(maxIOPS),
+		DynamicDistribution: distribution,
+	})
+}
+
+// ClearStorageGroupIOLimits removes the host I/O limits of a storage group.
+func (c *Client) ClearStorageGroupIOLimits(ctx context.Context, symID string, storageGroupID string) (*types.StorageGroup, error) {
+	defer c.TimeSpent("ClearStorageGroupIOLimits", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	return c.updateStorageGroupIOLimits(ctx, symID, storageGroupID, &types.SetHostIOLimitsParam{
+		HostIOLimitMBSec:    hostIOLimitNone,
+		HostIOLimitIOSec:    hostIOLimitNone,
+		DynamicDistribution: HostIOLimitDistributionNever,
+	})
+}
+
+// setIOLimitThroughput fills in the observed throughput of a storage group from its performance metrics over
+// IOLimitMetricsWindow.
+func setIOLimitThroughput(usage *StorageGroupIOLimitUsage, info types.StorageGroupInfo, threshold float64,
+	fetch func(start, end int64) ([]types.StorageGroupMetric, error),
+) error {
+	mbps := func(metric types.StorageGroupMetric) float64 { return metric.HostMBReads + metric.HostMBWritten }
+	iops := func(metric types.StorageGroupMetric) float64 { return metric.HostReads + metric.HostWrites }
+	averages, metrics, err := averageWindowMetrics(info.FirstAvailableDate, info.LastAvailableDate, IOLimitMetricsWindow, fetch, mbps, iops)
+	if err != nil || averages == nil {
+		return err
+	}
+	usage.MetricsKnown = true
+	usage.AverageMBps, usage.AverageIOPS = averages[0], averages[1]
+	for _, metric := range metrics {
+		usage.PeakMBps = max(usage.PeakMBps, mbps(metric))
+		usage.PeakIOPS = max(usage.PeakIOPS, iops(metric))
+	}
+	if usage.MaxMBps > 0 {
+		usage.MBpsPercent = usage.PeakMBps / float64(usage.MaxMBps) * 100
+	}
+	if usage.MaxIOPS > 0 {
+		usage.IOPSPercent = usage.PeakIOPS / float64(usage.MaxIOPS) * 100
+	}
+	usage.AtLimit = usage.MBpsPercent >= threshold || usage.IOPSPercent >= threshold
+	return nil
+}
+
+// GetStorageGroupIOLimitReport returns the storage groups of an array which have host I/O limits, with their
+// throughput over IOLimitMetricsWindow. Storage groups whose peak MB/s or IO/s reached threshold percent of the
+// limit are flagged; DefaultIOLimitThreshold is used if threshold is not positive.
+func (c *Client) GetStorageGroupIOLimitReport(ctx context.Context, symID string, threshold float64) (*StorageGroupIOLimitReport, error) {
+	defer c.TimeSpent("GetStorageGroupIOLimitReport", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	if threshold <= 0 {
+		threshold = DefaultIOLimitThreshold
+	}
+	sgIDList, err := c.GetStorageGroupIDList(ctx, symID, "", false)
+	if err != nil {
+		return nil, err
+	}
+	report := &StorageGroupIOLimitReport{
+		SymmetrixID:   symID,
+		Threshold:     threshold,
+		StorageGroups: make([]StorageGroupIOLimitUsage, 0),
+		Errors:        make(map[string]string),
+	}
+	var mu sync.Mutex
+	if err := runBounded(ctx, DefaultRequestConcurrency, len(sgIDList.StorageGroupIDs), func(i int) {
+		sg, err := c.GetStorageGroup(ctx, symID, sgIDList.StorageGroupIDs[i])
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			report.Errors[sgIDList.StorageGroupIDs[i]] = err.Error()
+			return
+		}
+		if sg.HostIOLimit == nil {
+			return
+		}
+		usage := StorageGroupIOLimitUsage{
+			StorageGroupID: sg.StorageGroupID,
+			MaxMBps:        parseHostIOLimit(sg.HostIOLimit.HostIOLimitMBSec),
+			MaxIOPS:        parseHostIOLimit(sg.HostIOLimit.HostIOLimitIOSec),
+			Distribution:   sg.HostIOLimit.DynamicDistribution,
+		}
+		if usage.MaxMBps > 0 || usage.MaxIOPS > 0 {
+			report.StorageGroups = append(report.StorageGroups, usage)
+		}
+	}); err != nil {
+		return nil, err
+	}
+	if len(report.StorageGroups) == 0 {
+		return report, nil
+	}
+	sort.Slice(report.StorageGroups, func(i, j int) bool {
+		return report.StorageGroups[i].StorageGroupID < report.StorageGroups[j].StorageGroupID
+	})
+
+	// Throughput is informational, so storage groups without performance data are not an error
+	available, err := c.getStorageGroupPerfInfos(ctx, symID)
+	if err != nil {
+		log.Warnf("no storage group throughput for %s: %s", symID, err.Error())
+		return report, nil
+	}
+	if err := runBounded(ctx, DefaultRequestConcurrency, len(report.StorageGroups), func(i int) {
+		usage := &report.StorageGroups[i]
+		info, ok := available[usage.StorageGroupID]
+		if !ok {
+			return
+		}
+		err := setIOLimitThroughput(usage, info, threshold, func(start, end int64) ([]types.StorageGroupMetric, error) {
+			metrics, err := c.GetStorageGroupMetrics(ctx, symID, usage.StorageGroupID, []string{"HostMBReads", "HostMBWritten", "HostReads", "HostWrites"}, start, end)
+			if err != nil {
+				return nil, err
+			}
+			return metrics.ResultList.Result, nil
+		})
+		if err != nil {
+			log.Warnf("no throughput for storage group %s: %s", usage.StorageGroupID, err.Error())
+		}
+	}); err != nil {
+		return nil, err
+	}
+	for _, usage := range report.StorageGroups {
+		if usage.AtLimit {
+			report.AtLimit++
+		}
+	}
+	return report, nil
+}
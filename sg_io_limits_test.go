@@ -0,0 +1,161 @@
+/*
+ *
+ * Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pmax
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dell/gopowermax/v2/mock"
+	types "github.com/dell/gopowermax/v2/types/v100"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetStorageGroupIOLimits(t *testing.T) {
+	c := newMockReplicationClient(t)
+	ctx := context.Background()
+	symID := mock.DefaultSymmetrixID
+	sgID := mock.DefaultStorageGroup
+
+	sg, err := c.SetStorageGroupIOLimits(ctx, symID, sgID, 100, 0, "")
+	assert.NoError(t, err)
+	assert.Equal(t, &types.SetHostIOLimitsParam{HostIOLimitMBSec: "100", HostIOLimitIOSec: "NOLIMIT", DynamicDistribution: HostIOLimitDistributionNever}, sg.HostIOLimit)
+
+	sg, err = c.SetStorageGroupIOLimits(ctx, symID, sgID, 200, 5000, HostIOLimitDistributionOnFailure)
+	assert.NoError(t, err)
+	assert.Equal(t, &types.SetHostIOLimitsParam{HostIOLimitMBSec: "200", HostIOLimitIOSec: "5000", DynamicDistribution: HostIOLimitDistributionOnFailure}, sg.HostIOLimit)
+
+	sg, err = c.ClearStorageGroupIOLimits(ctx, symID, sgID)
+	assert.NoError(t, err)
+	assert.Nil(t, sg.HostIOLimit)
+
+	tests := []struct {
+		name         string
+		maxMBps      int
+		maxIOPS      int
+		distribution string
+	}{
+		{name: "no limits"},
+		{name: "negative", maxMBps: -1},
+		{name: "iops not a multiple of 100", maxIOPS: 150},
+		{name: "bad distribution", maxMBps: 10, distribution: "Sometimes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := c.SetStorageGroupIOLimits(ctx, symID, sgID, tt.maxMBps, tt.maxIOPS, tt.distribution)
+			assert.Error(t, err)
+		})
+	}
+
+	mock.InducedErrors.UpdateStorageGroupError = true
+	_, err = c.SetStorageGroupIOLimits(ctx, symID, sgID, 100, 0, "")
+	assert.Error(t, err)
+	mock.InducedErrors.UpdateStorageGroupError = false
+
+	_, err = c.ClearStorageGroupIOLimits(ctx, "000000000002", sgID)
+	assert.Error(t, err)
+}
+
+// newIOLimitServer serves sg_mbps (100 MB/s), sg_iops (1000 IO/s), sg_nodata (1000 IO/s, no performance data)
+// and sg_open (no limits).
+func newIOLimitServer() *httptest.Server {
+	storageGroups := map[string]*types.StorageGroup{
+		"sg_mbps":   {StorageGroupID: "sg_mbps", HostIOLimit: &types.SetHostIOLimitsParam{HostIOLimitMBSec: "100", HostIOLimitIOSec: "NOLIMIT", DynamicDistribution: "Never"}},
+		"sg_iops":   {StorageGroupID: "sg_iops", HostIOLimit: &types.SetHostIOLimitsParam{HostIOLimitMBSec: "NOLIMIT", HostIOLimitIOSec: "1000", DynamicDistribution: "Always"}},
+		"sg_nodata": {StorageGroupID: "sg_nodata", HostIOLimit: &types.SetHostIOLimitsParam{HostIOLimitIOSec: "1000"}},
+		"sg_open":   {StorageGroupID: "sg_open"},
+	}
+	metrics := map[string][]types.StorageGroupMetric{
+		"sg_mbps": {{HostMBReads: 40, HostMBWritten: 20}, {HostMBReads: 70, HostMBWritten: 28}},
+		"sg_iops": {{HostReads: 300, HostWrites: 100}, {HostReads: 500, HostWrites: 100}},
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := r.URL.Path
+		last := path[strings.LastIndex(path, "/")+1:]
+		var out interface{}
+		switch {
+		case strings.HasSuffix(path, XStorageGroup):
+			out = &types.StorageGroupIDList{StorageGroupIDs: []string{"sg_open", "sg_nodata", "sg_mbps", "sg_iops"}}
+		case strings.Contains(path, XStorageGroup+"/") && storageGroups[last] != nil:
+			out = storageGroups[last]
+		case strings.HasSuffix(path, StorageGroup+Keys):
+			out = &types.StorageGroupKeysResult{StorageGroupInfos: []types.StorageGroupInfo{
+				{StorageGroupID: "sg_mbps", LastAvailableDate: 3600000},
+				{StorageGroupID: "sg_iops", LastAvailableDate: 3600000},
+			}}
+		case strings.HasSuffix(path, StorageGroup+Metrics):
+			params := &types.StorageGroupMetricsParam{}
+			_ = json.NewDecoder(r.Body).Decode(params)
+			out = &types.StorageGroupMetricsIterator{ResultList: types.StorageGroupMetricsResultList{Result: metrics[params.StorageGroupID]}}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"not found"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(out)
+	}))
+}
+
+func TestGetStorageGroupIOLimitReport(t *testing.T) {
+	symID := "000000000001"
+	server := newIOLimitServer()
+	defer server.Close()
+	c, err := NewClientWithArgs(server.URL, "", true, true, "")
+	assert.NoError(t, err)
+	c.SetAllowedArrays([]string{symID})
+
+	report, err := c.GetStorageGroupIOLimitReport(context.Background(), symID, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultIOLimitThreshold, report.Threshold)
+	assert.Empty(t, report.Errors)
+	assert.Len(t, report.StorageGroups, 3)
+	assert.Equal(t, 1, report.AtLimit)
+
+	iops := report.StorageGroups[0]
+	assert.Equal(t, "sg_iops", iops.StorageGroupID)
+	assert.Equal(t, 1000, iops.MaxIOPS)
+	assert.Equal(t, 0, iops.MaxMBps)
+	assert.Equal(t, "Always", iops.Distribution)
+	assert.Equal(t, 500.0, iops.AverageIOPS)
+	assert.Equal(t, 600.0, iops.PeakIOPS)
+	assert.Equal(t, 60.0, iops.IOPSPercent)
+	assert.False(t, iops.AtLimit)
+
+	mbps := report.StorageGroups[1]
+	assert.Equal(t, "sg_mbps", mbps.StorageGroupID)
+	assert.Equal(t, 100, mbps.MaxMBps)
+	assert.Equal(t, 79.0, mbps.AverageMBps)
+	assert.Equal(t, 98.0, mbps.MBpsPercent)
+	assert.True(t, mbps.AtLimit)
+
+	assert.Equal(t, "sg_nodata", report.StorageGroups[2].StorageGroupID)
+	assert.False(t, report.StorageGroups[2].MetricsKnown)
+
+	report, err = c.GetStorageGroupIOLimitReport(context.Background(), symID, 50)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, report.AtLimit)
+
+	_, err = c.GetStorageGroupIOLimitReport(context.Background(), "000000000002", 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,307 @@
+/*
+ Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pmax
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+	log "github.com/sirupsen/logrus"
+)
+
+// Operations of StorageGroupChangeProgress
+const (
+	StorageGroupChangeServiceLevel = "service_level"
+	StorageGroupChangeSRP          = "srp"
+)
+
+var (
+	// StorageGroupCompliancePollCount is the number of times a changed storage group is polled for compliance.
+	StorageGroupCompliancePollCount = 20
+	// StorageGroupCompliancePollInterval is the time between compliance polls.
+	StorageGroupCompliancePollInterval = 3 * time.Second
+)
+
+// StorageGroupChangeProgress is reported as a service level or SRP change proceeds.
+type StorageGroupChangeProgress struct {
+	SymmetrixID    string
+	StorageGroupID string
+	Operation      string
+	Target         string
+	JobID          string
+	// JobStatus is the last status read of the job.
+	JobStatus string
+	// Percent is the share of the phases of the change completed: the job is accepted, starts running and
+	// succeeds, then the change shows on the storage group and the storage group is compliant. The array
+	// reports no progress within a running job, so Percent stays at 40 while it runs.
+	Percent int
+	// Compliance is set once the job has succeeded and the storage group is polled for compliance.
+	Compliance string
+	Done       bool
+}
+
+// storageGroupChangePhases is the number of phases counted by StorageGroupChangeProgress.Percent.
+const storageGroupChangePhases = 5
+
+// jobPhases returns the number of phases of a change completed by its job with the given status.
+func jobPhases(status string) int {
+	switch status {
+	case types.JobStatusRunning:
+		return 2
+	case types.JobStatusSucceeded:
+		return 3
+	default:
+		return 1
+	}
+}
+
+// setPhases sets Percent from the number of phases of the change completed.
+func (p *StorageGroupChangeProgress) setPhases(completed int) {
+	p.Percent = completed * 100 / storageGroupChangePhases
+}
+
+// StorageGroupChangeFunc receives the progress of a storage group change.
+type StorageGroupChangeFunc func(StorageGroupChangeProgress)
+
+// SRPHeadroomError is returned by MoveStorageGroupToSRP when the target SRP has too little free usable capacity.
+type SRPHeadroomError struct {
+	SRPID          string
+	StorageGroupID string
+	RequiredGB     float64
+	HeadroomGB     float64
+}
+
+func (e *SRPHeadroomError) Error() string {
+	return fmt.Sprintf("SRP %s has %.2f GB free but storage group %s needs %.2f GB", e.SRPID, e.HeadroomGB, e.StorageGroupID, e.RequiredGB)
+}
+
+// runStorageGroupChange submits a storage group change as a job, waits for the job reporting its status, then
+// polls the storage group until applied reports the change is visible and the storage group is compliant.
+// If the storage group is not yet compliant after StorageGroupCompliancePollCount polls it is returned as is.
+func (c *Client) runStorageGroupChange(ctx context.Context, symID string, storageGroupID string, edit types.EditStorageGroupActionParam,
+	progress StorageGroupChangeProgress, applied func(*types.StorageGroup) bool, report StorageGroupChangeFunc,
+) (*types.StorageGroup, error) {
+	notify := func() {
+		if report != nil {
+			report(progress)
+		}
+	}
+	payload := &types.UpdateStorageGroupPayload{
+		EditStorageGroupActionParam: edit,
+		ExecutionOption:             types.ExecutionOptionAsynchronous,
+	}
+	ifDebugLogPayload(payload)
+	job, err := c.UpdateStorageGroup(ctx, symID, storageGroupID, payload)
+	if err != nil {
+		return nil, err
+	}
+	progress.JobID = job.JobID
+	progress.JobStatus = job.Status
+	progress.setPhases(jobPhases(job.Status))
+	notify()
+	job, err = c.waitOnJob(ctx, symID, job.JobID, func(job *types.Job) {
+		if job.Status == progress.JobStatus || job.Status == types.JobStatusSucceeded || job.Status == types.JobStatusFailed {
+			return
+		}
+		progress.JobStatus = job.Status
+		progress.setPhases(jobPhases(job.Status))
+		notify()
+	})
+	if err != nil {
+		return nil, err
+	}
+	if job.Status == types.JobStatusFailed {
+		return nil, fmt.Errorf("job %s to change the %s of storage group %s failed: %s", job.JobID, progress.Operation, storageGroupID, job.Result)
+	}
+	progress.JobStatus = job.Status
+	progress.setPhases(jobPhases(job.Status))
+
+	var sg *types.StorageGroup
+	for i := 0; i < StorageGroupCompliancePollCount; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(StorageGroupCompliancePollInterval):
+			}
+		}
+		if sg, err = c.GetStorageGroup(ctx, symID, storageGroupID); err != nil {
+			return nil, err
+		}
+		progress.Compliance = sg.SLOCompliance
+		if applied(sg) {
+			progress.setPhases(storageGroupChangePhases - 1)
+		}
+		if applied(sg) && (sg.SLOCompliance == "" || sg.SLOCompliance == "NONE" || sg.SLOCompliance == SLOComplianceStable) {
+			progress.setPhases(storageGroupChangePhases)
+			progress.Done = true
+			notify()
+			return sg, nil
+		}
+		notify()
+	}
+	log.Warnf("storage group %s is %s after its %s change to %s", storageGroupID, sg.SLOCompliance, progress.Operation, progress.Target)
+	return sg, nil
+}
+
+// checkCascadeServiceLevel checks that a storage group may have a service level, which is not the case if its
+// parent or one of its children has one.
+func (c *Client) checkCascadeServiceLevel(ctx context.Context, symID string, sg *types.StorageGroup) error {
+	for _, relatedIDs := range [][]string{sg.ParentStorageGroup, sg.ChildStorageGroup} {
+		for _, relatedID := range relatedIDs {
+			related, err := c.GetStorageGroup(ctx, symID, relatedID)
+			if err != nil {
+				return err
+			}
+			if hasServiceLevel(related) {
+				return fmt.Errorf("storage group %s cannot have a service level as %s has service level %s",
+					sg.StorageGroupID, relatedID, storageGroupServiceLevel(related))
+			}
+		}
+	}
+	return nil
+}
+
+// checkSRPServiceLevel checks that an SRP offers a service level, if the SRP lists its service levels.
+func checkSRPServiceLevel(srp *types.StoragePool, serviceLevel string) error {
+	if serviceLevel == "None" || len(srp.ServiceLevels) == 0 {
+		return nil
+	}
+	for _, offered := range srp.ServiceLevels {
+		if strings.EqualFold(offered, serviceLevel) {
+			return nil
+		}
+	}
+	return fmt.Errorf("service level %s is not offered by SRP %s", serviceLevel, srp.StoragePoolID)
+}
+
+// ChangeStorageGroupServiceLevel changes the service level of a storage group, which must be offered by its SRP.
+// The change runs as a job; progress, if not nil, is called as the job proceeds and as the storage group is
+// polled for compliance. A service level of "None" removes the service level.
+func (c *Client) ChangeStorageGroupServiceLevel(ctx context.Context, symID string, storageGroupID string, serviceLevel string, progress StorageGroupChangeFunc) (*types.StorageGroup, error) {
+	defer c.TimeSpent("ChangeStorageGroupServiceLevel", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	if serviceLevel == "" {
+		return nil, fmt.Errorf("a service level is required for storage group %s", storageGroupID)
+	}
+	sg, err := c.GetStorageGroup(ctx, symID, storageGroupID)
+	if err != nil {
+		return nil, err
+	}
+	applied := func(sg *types.StorageGroup) bool {
+		return strings.EqualFold(storageGroupServiceLevel(sg), serviceLevel)
+	}
+	status := StorageGroupChangeProgress{
+		SymmetrixID:    symID,
+		StorageGroupID: storageGroupID,
+		Operation:      StorageGroupChangeServiceLevel,
+		Target:         serviceLevel,
+	}
+	if applied(sg) {
+		log.Debugf("storage group %s already has service level %s", storageGroupID, serviceLevel)
+		return sg, nil
+	}
+	if serviceLevel != "None" {
+		if sg.SRP == "" || sg.SRP == "None" {
+			return nil, fmt.Errorf("storage group %s has no SRP to provide service level %s", storageGroupID, serviceLevel)
+		}
+		if err = c.checkCascadeServiceLevel(ctx, symID, sg); err != nil {
+			log.Error("ChangeStorageGroupServiceLevel failed: " + err.Error())
+			return nil, err
+		}
+		srp, err := c.GetStoragePool(ctx, symID, sg.SRP)
+		if err != nil {
+			return nil, err
+		}
+		if err = checkSRPServiceLevel(srp, serviceLevel); err != nil {
+			return nil, err
+		}
+	}
+	return c.runStorageGroupChange(ctx, symID, storageGroupID, types.EditStorageGroupActionParam{
+		EditStorageGroupSLOParam: &types.EditStorageGroupSLOParam{SLOID: serviceLevel},
+	}, status, applied, progress)
+}
+
+// getStorageGroupAllocatedGB returns the capacity used by the volumes of a storage group, read in bulk with a
+// v1 volumes query filtered to the storage group.
+func (c *Client) getStorageGroupAllocatedGB(ctx context.Context, symID string, storageGroupID string) (float64, error) {
+	filter := strings.ReplaceAll(url.QueryEscape("storage_groups.id EQ "+storageGroupID), "+", "%20")
+	volumes, err := c.getVolumesV1(ctx, c.urlPrefixV1()+symID+XVolumeV1+SelectQuery+SelectID+SelectCapGB+
+		SelectEffectiveUsedCapGB+SelectStorageGroupID+"&filter="+filter+"&limit=1000&expiration_delay_secs=30")
+	if err != nil {
+		return 0, err
+	}
+	return storageGroupAllocations(volumes)[storageGroupID], nil
+}
+
+// MoveStorageGroupToSRP moves a storage group to another SRP, keeping its service level, which the SRP must offer.
+// The target SRP must have free usable capacity for the capacity allocated to the storage group's volumes, otherwise
+// an *SRPHeadroomError is returned. The move runs as a job; progress, if not nil, is called as the job proceeds and
+// as the storage group is polled for compliance.
+func (c *Client) MoveStorageGroupToSRP(ctx context.Context, symID string, storageGroupID string, srpID string, progress StorageGroupChangeFunc) (*types.StorageGroup, error) {
+	defer c.TimeSpent("MoveStorageGroupToSRP", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	if srpID == "" || srpID == "None" {
+		return nil, fmt.Errorf("a target SRP is required for storage group %s", storageGroupID)
+	}
+	sg, err := c.GetStorageGroup(ctx, symID, storageGroupID)
+	if err != nil {
+		return nil, err
+	}
+	applied := func(sg *types.StorageGroup) bool {
+		return sg.SRP == srpID
+	}
+	status := StorageGroupChangeProgress{
+		SymmetrixID:    symID,
+		StorageGroupID: storageGroupID,
+		Operation:      StorageGroupChangeSRP,
+		Target:         srpID,
+	}
+	if applied(sg) {
+		log.Debugf("storage group %s is already in SRP %s", storageGroupID, srpID)
+		return sg, nil
+	}
+	srp, err := c.GetStoragePool(ctx, symID, srpID)
+	if err != nil {
+		return nil, err
+	}
+	if srp.SrpCap == nil {
+		return nil, fmt.Errorf("no capacity reported for SRP %s", srpID)
+	}
+	if err = checkSRPServiceLevel(srp, storageGroupServiceLevel(sg)); err != nil {
+		return nil, err
+	}
+	requiredGB, err := c.getStorageGroupAllocatedGB(ctx, symID, storageGroupID)
+	if err != nil {
+		return nil, err
+	}
+	headroomGB := (srp.SrpCap.UsableTotInTB - srp.SrpCap.UsableUsedInTB) * 1024
+	if requiredGB > headroomGB {
+		err := &SRPHeadroomError{SRPID: srpID, StorageGroupID: storageGroupID, RequiredGB: requiredGB, HeadroomGB: headroomGB}
+		log.Error("MoveStorageGroupToSRP failed: " + err.Error())
+		return nil, err
+	}
+	return c.runStorageGroupChange(ctx, symID, storageGroupID, types.EditStorageGroupActionParam{
+		EditStorageGroupSRPParam: &types.EditStorageGroupSRPParam{SRPID: srpID},
+	}, status, applied, progress)
+}
@@ -0,0 +1,128 @@
+/*
+ *
+ * Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pmax
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dell/gopowermax/v2/mock"
+	types "github.com/dell/gopowermax/v2/types/v100"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangeStorageGroupServiceLevel(t *testing.T) {
+	sleep, interval := JobRetrySleepDuration, StorageGroupCompliancePollInterval
+	JobRetrySleepDuration, StorageGroupCompliancePollInterval = 0, 0
+	defer func() { JobRetrySleepDuration, StorageGroupCompliancePollInterval = sleep, interval }()
+
+	c := newMockReplicationClient(t)
+	ctx := context.Background()
+	symID := mock.DefaultSymmetrixID
+	_, err := mock.AddStorageGroup("slo-sg", "SRP_1", "Bronze")
+	assert.NoError(t, err)
+
+	updates := make([]StorageGroupChangeProgress, 0)
+	sg, err := c.ChangeStorageGroupServiceLevel(ctx, symID, "slo-sg", "Diamond", func(p StorageGroupChangeProgress) { updates = append(updates, p) })
+	assert.NoError(t, err)
+	assert.Equal(t, "Diamond", sg.SLO)
+	if assert.Len(t, updates, 2) {
+		assert.Equal(t, StorageGroupChangeServiceLevel, updates[0].Operation)
+		assert.Equal(t, "Diamond", updates[0].Target)
+		assert.NotEmpty(t, updates[0].JobID)
+		assert.Equal(t, types.JobStatusRunning, updates[0].JobStatus)
+		assert.Equal(t, 40, updates[0].Percent)
+		assert.False(t, updates[0].Done)
+		assert.Equal(t, types.JobStatusSucceeded, updates[1].JobStatus)
+		assert.Equal(t, 100, updates[1].Percent)
+		assert.Equal(t, SLOComplianceStable, updates[1].Compliance)
+		assert.True(t, updates[1].Done)
+	}
+
+	// already set, so nothing to do
+	updates = updates[:0]
+	_, err = c.ChangeStorageGroupServiceLevel(ctx, symID, "slo-sg", "diamond", func(p StorageGroupChangeProgress) { updates = append(updates, p) })
+	assert.NoError(t, err)
+	assert.Empty(t, updates)
+
+	_, err = mock.AddStorageGroup("slo-child", "SRP_1", "")
+	assert.NoError(t, err)
+	_, err = c.AddChildStorageGroup(ctx, symID, "slo-sg", "slo-child")
+	assert.NoError(t, err)
+	_, err = c.ChangeStorageGroupServiceLevel(ctx, symID, "slo-child", "Gold", nil)
+	assert.ErrorContains(t, err, "has service level Diamond")
+
+	mock.InducedErrors.JobFailedError = true
+	_, err = c.ChangeStorageGroupServiceLevel(ctx, symID, "slo-sg", "Gold", nil)
+	assert.ErrorContains(t, err, "failed")
+	mock.InducedErrors.JobFailedError = false
+
+	_, err = c.ChangeStorageGroupServiceLevel(ctx, symID, "slo-sg", "", nil)
+	assert.Error(t, err)
+	_, err = c.ChangeStorageGroupServiceLevel(ctx, symID, "slo-none", "Gold", nil)
+	assert.Error(t, err)
+}
+
+func TestMoveStorageGroupToSRP(t *testing.T) {
+	sleep, interval := JobRetrySleepDuration, StorageGroupCompliancePollInterval
+	JobRetrySleepDuration, StorageGroupCompliancePollInterval = 0, 0
+	defer func() { JobRetrySleepDuration, StorageGroupCompliancePollInterval = sleep, interval }()
+
+	c := newMockReplicationClient(t)
+	ctx := context.Background()
+	symID := mock.DefaultSymmetrixID
+	_, err := mock.AddStorageGroup("srp-sg", "SRP_1", "Diamond")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.AddNewVolume("0E001", "srp-vol", 1, "srp-sg"))
+	// the mock SRP has 3.42 TB usable of which 1.39 TB is used
+	mock.Data.VolumeIDToVolume["0E001"].CapacityGB = 5000
+	mock.Data.VolumeIDToVolume["0E001"].AllocatedPercent = 50
+
+	_, err = c.MoveStorageGroupToSRP(ctx, symID, "srp-sg", "SRP_2", nil)
+	var headroomErr *SRPHeadroomError
+	if assert.True(t, errors.As(err, &headroomErr)) {
+		assert.Equal(t, 2500.0, headroomErr.RequiredGB)
+		assert.InDelta(t, 2078.72, headroomErr.HeadroomGB, 0.01)
+	}
+
+	mock.Data.VolumeIDToVolume["0E001"].AllocatedPercent = 10
+	var last StorageGroupChangeProgress
+	sg, err := c.MoveStorageGroupToSRP(ctx, symID, "srp-sg", "SRP_2", func(p StorageGroupChangeProgress) { last = p })
+	assert.NoError(t, err)
+	assert.Equal(t, "SRP_2", sg.SRP)
+	assert.Equal(t, "Diamond", sg.SLO)
+	assert.Equal(t, StorageGroupChangeSRP, last.Operation)
+	assert.Equal(t, 100, last.Percent)
+	assert.True(t, last.Done)
+
+	sg, err = c.MoveStorageGroupToSRP(ctx, symID, "srp-sg", "SRP_2", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "SRP_2", sg.SRP)
+
+	mock.InducedErrors.GetStoragePoolError = true
+	_, err = c.MoveStorageGroupToSRP(ctx, symID, "srp-sg", "SRP_1", nil)
+	assert.Error(t, err)
+	mock.InducedErrors.GetStoragePoolError = false
+
+	_, err = c.MoveStorageGroupToSRP(ctx, symID, "srp-sg", "", nil)
+	assert.Error(t, err)
+	_, err = c.MoveStorageGroupToSRP(ctx, "000000000002", "srp-sg", "SRP_1", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,302 @@
+/*
+ Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pmax
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	// MAXSnapStateRetryCount is the maximum number of polls while waiting for a storage group snap
+	// to reach a terminal state. It is a variable so that unit testing can set it lower.
+	MAXSnapStateRetryCount = 60
+	// SnapStateRetrySleepDuration is the amount of time between polls of a storage group snap.
+	SnapStateRetrySleepDuration = 5 * time.Second
+)
+
+type sgSnapSelectorKind int
+
+const (
+	sgSnapBySnapID sgSnapSelectorKind = iota
+	sgSnapByGeneration
+	sgSnapByTimestamp
+)
+
+// SGSnapSelector identifies a single snap of a named storage group snapshot.
+// Use SGSnapBySnapID, SGSnapByGeneration or SGSnapByTimestamp to build one.
+type SGSnapSelector struct {
+	kind       sgSnapSelectorKind
+	snapID     int64
+	generation int64
+	timestamp  time.Time
+}
+
+// SGSnapBySnapID selects a snap by its array assigned snap ID.
+func SGSnapBySnapID(snapID int64) SGSnapSelector {
+	return SGSnapSelector{kind: sgSnapBySnapID, snapID: snapID}
+}
+
+// SGSnapByGeneration selects a snap by generation number, where 0 is the newest.
+func SGSnapByGeneration(generation int64) SGSnapSelector {
+	return SGSnapSelector{kind: sgSnapByGeneration, generation: generation}
+}
+
+// SGSnapByTimestamp selects the snap taken at the given time, matched to the second.
+func SGSnapByTimestamp(timestamp time.Time) SGSnapSelector {
+	return SGSnapSelector{kind: sgSnapByTimestamp, timestamp: timestamp}
+}
+
+func (s SGSnapSelector) String() string {
+	switch s.kind {
+	case sgSnapByGeneration:
+		return fmt.Sprintf("generation %d", s.generation)
+	case sgSnapByTimestamp:
+		return fmt.Sprintf("timestamp %s", s.timestamp.UTC().Format(time.RFC3339))
+	default:
+		return fmt.Sprintf("snapid %d", s.snapID)
+	}
+}
+
+func (s SGSnapSelector) matches(snap *types.StorageGroupSnap) bool {
+	switch s.kind {
+	case sgSnapByGeneration:
+		return snap.Generation == s.generation
+	case sgSnapByTimestamp:
+		return snap.TimestampUtc/1000 == s.timestamp.Unix()
+	default:
+		return snap.SnapID == s.snapID
+	}
+}
+
+// ResolveSGSnapshotSnap returns the snap of storage group snapshot snapshotID chosen by the selector.
+func (c *Client) ResolveSGSnapshotSnap(ctx context.Context, symID, storageGroupID, snapshotID string, selector SGSnapSelector) (*types.StorageGroupSnap, error) {
+	defer c.TimeSpent("ResolveSGSnapshotSnap", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	if selector.kind == sgSnapBySnapID {
+		return c.GetStorageGroupSnapshotSnap(ctx, symID, storageGroupID, snapshotID, strconv.FormatInt(selector.snapID, 10))
+	}
+	snapIDs, err := c.GetStorageGroupSnapshotSnapIDs(ctx, symID, storageGroupID, snapshotID)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range snapIDs.SnapIDs {
+		snap, err := c.GetStorageGroupSnapshotSnap(ctx, symID, storageGroupID, snapshotID, strconv.FormatInt(id, 10))
+		if err != nil {
+			return nil, err
+		}
+		if selector.matches(snap) {
+			return snap, nil
+		}
+	}
+	return nil, fmt.Errorf("no snap with %s found for snapshot %s of StorageGroup %s", selector, snapshotID, storageGroupID)
+}
+
+// LinkSGSnapshot links the selected snap to the target storage group named in link
+// and waits until the link is defined (and fully copied when link.Copy is set).
+func (c *Client) LinkSGSnapshot(ctx context.Context, symID, storageGroupID, snapshotID string, selector SGSnapSelector, link types.LinkSnapshotAction) (*types.StorageGroupSnap, error) {
+	defer c.TimeSpent("LinkSGSnapshot", time.Now())
+	if link.StorageGroupName == "" {
+		return nil, fmt.Errorf("a target storage group name is required to link snapshot %s", snapshotID)
+	}
+	payload := &types.ModifyStorageGroupSnapshot{
+		Action: string(Link),
+		Link:   link,
+	}
+	return c.modifySGSnapshotAndWait(ctx, symID, storageGroupID, snapshotID, selector, payload, sgSnapLinked(link.StorageGroupName, link.Copy))
+}
+
+// RelinkSGSnapshot relinks the selected snap to the already linked target storage group named in relink
+// and waits until the link is defined (and fully copied when relink.Copy is set).
+func (c *Client) RelinkSGSnapshot(ctx context.Context, symID, storageGroupID, snapshotID string, selector SGSnapSelector, relink types.RelinkSnapshotAction) (*types.StorageGroupSnap, error) {
+	defer c.TimeSpent("RelinkSGSnapshot", time.Now())
+	if relink.StorageGroupName == "" {
+		return nil, fmt.Errorf("a target storage group name is required to relink snapshot %s", snapshotID)
+	}
+	payload := &types.ModifyStorageGroupSnapshot{
+		Action: string(Relink),
+		Relink: relink,
+	}
+	return c.modifySGSnapshotAndWait(ctx, symID, storageGroupID, snapshotID, selector, payload, sgSnapLinked(relink.StorageGroupName, relink.Copy))
+}
+
+// UnlinkSGSnapshot unlinks the selected snap from the target storage group named in unlink
+// and waits until the target no longer shows as linked.
+func (c *Client) UnlinkSGSnapshot(ctx context.Context, symID, storageGroupID, snapshotID string, selector SGSnapSelector, unlink types.UnlinkSnapshotAction) (*types.StorageGroupSnap, error) {
+	defer c.TimeSpent("UnlinkSGSnapshot", time.Now())
+	if unlink.StorageGroupName == "" {
+		return nil, fmt.Errorf("a target storage group name is required to unlink snapshot %s", snapshotID)
+	}
+	payload := &types.ModifyStorageGroupSnapshot{
+		Action: string(Unlink),
+		Unlink: unlink,
+	}
+	return c.modifySGSnapshotAndWait(ctx, symID, storageGroupID, snapshotID, selector, payload, sgSnapUnlinked(unlink.StorageGroupName))
+}
+
+// RestoreSGSnapshot restores the selected snap onto its source storage group and waits for the restore to complete.
+func (c *Client) RestoreSGSnapshot(ctx context.Context, symID, storageGroupID, snapshotID string, selector SGSnapSelector, restore types.RestoreSnapshotAction) (*types.StorageGroupSnap, error) {
+	defer c.TimeSpent("RestoreSGSnapshot", time.Now())
+	payload := &types.ModifyStorageGroupSnapshot{
+		Action:  string(Restore),
+		Restore: restore,
+	}
+	return c.modifySGSnapshotAndWait(ctx, symID, storageGroupID, snapshotID, selector, payload, sgSnapRestored)
+}
+
+// SetSGSnapshotTTL sets the time to live of the selected snap, in days or in hours when inHours is set.
+func (c *Client) SetSGSnapshotTTL(ctx context.Context, symID, storageGroupID, snapshotID string, selector SGSnapSelector, ttl int32, inHours bool) (*types.StorageGroupSnap, error) {
+	defer c.TimeSpent("SetSGSnapshotTTL", time.Now())
+	payload := &types.ModifyStorageGroupSnapshot{
+		Action: string(SetTimeToLive),
+		TimeToLive: types.TimeToLiveSnapshotAction{
+			TimeToLive:  ttl,
+			TimeInHours: inHours,
+		},
+	}
+	return c.modifySGSnapshotAndWait(ctx, symID, storageGroupID, snapshotID, selector, payload, nil)
+}
+
+// SecureSGSnapshot secures the selected snap for the given number of days, or hours when inHours is set.
+func (c *Client) SecureSGSnapshot(ctx context.Context, symID, storageGroupID, snapshotID string, selector SGSnapSelector, secure int32, inHours bool) (*types.StorageGroupSnap, error) {
+	defer c.TimeSpent("SecureSGSnapshot", time.Now())
+	if secure <= 0 {
+		return nil, fmt.Errorf("secure period must be positive, got %d", secure)
+	}
+	payload := &types.ModifyStorageGroupSnapshot{
+		Action: string(SetSecure),
+		Secure: types.SecureSnapshotAction{
+			Secure:      secure,
+			TimeInHours: inHours,
+		},
+	}
+	return c.modifySGSnapshotAndWait(ctx, symID, storageGroupID, snapshotID, selector, payload, nil)
+}
+
+// sgSnapDone reports whether a snap has reached the terminal state of an action.
+type sgSnapDone func(snap *types.StorageGroupSnap) (bool, error)
+
+// modifySGSnapshotAndWait resolves the selected snap, applies payload to it and, if done is not nil,
+// polls the snap until done reports a terminal state.
+func (c *Client) modifySGSnapshotAndWait(ctx context.Context, symID, storageGroupID, snapshotID string, selector SGSnapSelector,
+	payload *types.ModifyStorageGroupSnapshot, done sgSnapDone,
+) (*types.StorageGroupSnap, error) {
+	snap, err := c.ResolveSGSnapshotSnap(ctx, symID, storageGroupID, snapshotID, selector)
+	if err != nil {
+		return nil, err
+	}
+	snapID := strconv.FormatInt(snap.SnapID, 10)
+	payload.ExecutionOption = types.ExecutionOptionSynchronous
+	snap, err = c.ModifyStorageGroupSnapshot(ctx, symID, storageGroupID, snapshotID, snapID, payload)
+	if err != nil {
+		return nil, err
+	}
+	if done == nil {
+		return snap, nil
+	}
+	for i := 0; i < MAXSnapStateRetryCount; i++ {
+		snap, err = c.GetStorageGroupSnapshotSnap(ctx, symID, storageGroupID, snapshotID, snapID)
+		if err != nil {
+			return nil, err
+		}
+		finished, err := done(snap)
+		if err != nil {
+			return snap, err
+		}
+		if finished {
+			log.Infof("%s of snapshot %s snapid %s on StorageGroup %s completed", payload.Action, snapshotID, snapID, storageGroupID)
+			return snap, nil
+		}
+		select {
+		case <-ctx.Done():
+			return snap, ctx.Err()
+		case <-time.After(SnapStateRetrySleepDuration):
+		}
+	}
+	return snap, fmt.Errorf("%s of snapshot %s snapid %s on StorageGroup %s timed out after %d retries, state %v",
+		payload.Action, snapshotID, snapID, storageGroupID, MAXSnapStateRetryCount, snap.State)
+}
+
+func sgSnapFailed(snap *types.StorageGroupSnap) error {
+	for _, state := range snap.State {
+		if strings.EqualFold(state, "Failed") {
+			return fmt.Errorf("snap %d of snapshot %s is in state %v", snap.SnapID, snap.Name, snap.State)
+		}
+	}
+	return nil
+}
+
+func sgSnapLinked(target string, copy bool) sgSnapDone {
+	return func(snap *types.StorageGroupSnap) (bool, error) {
+		if err := sgSnapFailed(snap); err != nil {
+			return false, err
+		}
+		// A copy can only be confirmed from the detailed link, the names alone are enough otherwise
+		found := !copy && stringInSlice(target, snap.LinkedStorageGroupNames)
+		for _, linked := range snap.LinkedStorageGroups {
+			if linked.Name != target {
+				continue
+			}
+			if !linked.Defined || linked.BackgroundDefineInProgress {
+				return false, nil
+			}
+			if copy && linked.PercentageCopied < 100 {
+				return false, nil
+			}
+			found = true
+		}
+		return found, nil
+	}
+}
+
+func sgSnapUnlinked(target string) sgSnapDone {
+	return func(snap *types.StorageGroupSnap) (bool, error) {
+		if err := sgSnapFailed(snap); err != nil {
+			return false, err
+		}
+		if stringInSlice(target, snap.LinkedStorageGroupNames) {
+			return false, nil
+		}
+		for _, linked := range snap.LinkedStorageGroups {
+			if linked.Name == target {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+}
+
+func sgSnapRestored(snap *types.StorageGroupSnap) (bool, error) {
+	if err := sgSnapFailed(snap); err != nil {
+		return false, err
+	}
+	if !snap.Restored {
+		return false, nil
+	}
+	for _, state := range snap.State {
+		if strings.Contains(strings.ToLower(state), "inprog") {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,334 @@
+/*
+ *
+ * Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pmax
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	sgSnapTestSymID = "000000000001"
+	sgSnapTestSG    = "sg_1"
+	sgSnapTestSnap  = "snap_1"
+)
+
+// fakeSGSnapArray serves the snapid endpoints of a single storage group snapshot.
+// Modifications only become visible after pendingPolls further GETs of the snap.
+type fakeSGSnapArray struct {
+	mu           sync.Mutex
+	snaps        map[string]*types.StorageGroupSnap
+	pending      map[string]*types.StorageGroupSnap
+	pendingPolls int
+	actions      []string
+	putSnapIDs   []string
+}
+
+func newFakeSGSnapArray(pendingPolls int) *fakeSGSnapArray {
+	base := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	return &fakeSGSnapArray{
+		snaps: map[string]*types.StorageGroupSnap{
+			"10": {Name: sgSnapTestSnap, SnapID: 10, Generation: 1, TimestampUtc: base.UnixMilli()},
+			"11": {Name: sgSnapTestSnap, SnapID: 11, Generation: 0, TimestampUtc: base.Add(time.Hour).UnixMilli() + 250},
+		},
+		pending:      map[string]*types.StorageGroupSnap{},
+		pendingPolls: pendingPolls,
+	}
+}
+
+func (f *fakeSGSnapArray) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	path := r.URL.Path
+	if strings.HasSuffix(path, SnapID) {
+		_ = json.NewEncoder(w).Encode(&types.SnapID{SnapIDs: []int64{10, 11}})
+		return
+	}
+	id := path[strings.LastIndex(path, "/")+1:]
+	snap, ok := f.snaps[id]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		if next, ok := f.pending[id]; ok {
+			if f.pendingPolls == 0 {
+				f.snaps[id] = next
+				snap = next
+				delete(f.pending, id)
+			} else {
+				f.pendingPolls--
+			}
+		}
+	case http.MethodPut:
+		payload := map[string]interface{}{}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		action, _ := payload["action"].(string)
+		f.actions = append(f.actions, action)
+		f.putSnapIDs = append(f.putSnapIDs, id)
+		next := *snap
+		switch action {
+		case string(Link), string(Relink):
+			target := payload[strings.ToLower(action)].(map[string]interface{})["storage_group_name"].(string)
+			next.Linked = true
+			next.LinkedStorageGroupNames = []string{target}
+			next.LinkedStorageGroups = []types.LinkedStorageGroup{{Name: target, Defined: true, PercentageCopied: 100}}
+			snap.Linked = true
+			snap.LinkedStorageGroups = []types.LinkedStorageGroup{{Name: target, BackgroundDefineInProgress: true}}
+		case string(Unlink):
+			next.Linked = false
+			next.LinkedStorageGroupNames = nil
+			next.LinkedStorageGroups = nil
+		case string(Restore):
+			next.Restored = true
+			next.State = []string{"Restored"}
+			snap.Restored = true
+			snap.State = []string{"RestoreInProg"}
+		case string(SetSecure):
+			next.SecureExpiryDate = "tomorrow"
+			snap = &next
+		case string(SetTimeToLive):
+			next.TimeToLiveExpiryDate = "tomorrow"
+			snap = &next
+		}
+		f.pending[id] = &next
+	}
+	_ = json.NewEncoder(w).Encode(snap)
+}
+
+func newSGSnapTestClient(t *testing.T, handler http.Handler) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	c, err := NewClientWithArgs(server.URL, "", true, true, "")
+	assert.NoError(t, err)
+	c.SetAllowedArrays([]string{sgSnapTestSymID})
+	return c.(*Client)
+}
+
+func TestResolveSGSnapshotSnap(t *testing.T) {
+	base := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name       string
+		selector   SGSnapSelector
+		wantSnapID int64
+		wantErr    string
+	}{
+		{name: "by snap id", selector: SGSnapBySnapID(10), wantSnapID: 10},
+		{name: "by generation", selector: SGSnapByGeneration(0), wantSnapID: 11},
+		{name: "by timestamp", selector: SGSnapByTimestamp(base.Add(time.Hour)), wantSnapID: 11},
+		{name: "unknown generation", selector: SGSnapByGeneration(7), wantErr: "no snap with generation 7"},
+		{name: "unknown timestamp", selector: SGSnapByTimestamp(base.Add(time.Minute)), wantErr: "no snap with timestamp"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newSGSnapTestClient(t, newFakeSGSnapArray(0))
+			snap, err := c.ResolveSGSnapshotSnap(context.Background(), sgSnapTestSymID, sgSnapTestSG, sgSnapTestSnap, tc.selector)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantSnapID, snap.SnapID)
+		})
+	}
+}
+
+func TestSGSnapshotActions(t *testing.T) {
+	defaultCount, defaultSleep := MAXSnapStateRetryCount, SnapStateRetrySleepDuration
+	defer func() {
+		MAXSnapStateRetryCount, SnapStateRetrySleepDuration = defaultCount, defaultSleep
+	}()
+	SnapStateRetrySleepDuration = time.Millisecond
+
+	tests := []struct {
+		name         string
+		pendingPolls int
+		retries      int
+		call         func(c *Client) (*types.StorageGroupSnap, error)
+		wantAction   string
+		wantErr      string
+		check        func(t *testing.T, snap *types.StorageGroupSnap)
+	}{
+		{
+			name:         "link waits for define",
+			pendingPolls: 2,
+			retries:      5,
+			call: func(c *Client) (*types.StorageGroupSnap, error) {
+				return c.LinkSGSnapshot(context.Background(), sgSnapTestSymID, sgSnapTestSG, sgSnapTestSnap, SGSnapByGeneration(0),
+					types.LinkSnapshotAction{StorageGroupName: "target_sg", Copy: true})
+			},
+			wantAction: string(Link),
+			check: func(t *testing.T, snap *types.StorageGroupSnap) {
+				assert.Equal(t, []string{"target_sg"}, snap.LinkedStorageGroupNames)
+				assert.True(t, snap.LinkedStorageGroups[0].Defined)
+			},
+		},
+		{
+			name:         "link times out",
+			pendingPolls: 10,
+			retries:      3,
+			call: func(c *Client) (*types.StorageGroupSnap, error) {
+				return c.LinkSGSnapshot(context.Background(), sgSnapTestSymID, sgSnapTestSG, sgSnapTestSnap, SGSnapByGeneration(0),
+					types.LinkSnapshotAction{StorageGroupName: "target_sg"})
+			},
+			wantAction: string(Link),
+			wantErr:    "timed out after 3 retries",
+		},
+		{
+			name: "link without target",
+			call: func(c *Client) (*types.StorageGroupSnap, error) {
+				return c.LinkSGSnapshot(context.Background(), sgSnapTestSymID, sgSnapTestSG, sgSnapTestSnap, SGSnapByGeneration(0),
+					types.LinkSnapshotAction{})
+			},
+			wantErr: "target storage group name is required",
+		},
+		{
+			name:    "relink",
+			retries: 2,
+			call: func(c *Client) (*types.StorageGroupSnap, error) {
+				return c.RelinkSGSnapshot(context.Background(), sgSnapTestSymID, sgSnapTestSG, sgSnapTestSnap, SGSnapBySnapID(10),
+					types.RelinkSnapshotAction{StorageGroupName: "target_sg"})
+			},
+			wantAction: string(Relink),
+		},
+		{
+			name:    "unlink",
+			retries: 2,
+			call: func(c *Client) (*types.StorageGroupSnap, error) {
+				return c.UnlinkSGSnapshot(context.Background(), sgSnapTestSymID, sgSnapTestSG, sgSnapTestSnap, SGSnapBySnapID(11),
+					types.UnlinkSnapshotAction{StorageGroupName: "target_sg"})
+			},
+			wantAction: string(Unlink),
+			check: func(t *testing.T, snap *types.StorageGroupSnap) {
+				assert.Empty(t, snap.LinkedStorageGroups)
+			},
+		},
+		{
+			name:         "restore waits for restored state",
+			pendingPolls: 1,
+			retries:      5,
+			call: func(c *Client) (*types.StorageGroupSnap, error) {
+				return c.RestoreSGSnapshot(context.Background(), sgSnapTestSymID, sgSnapTestSG, sgSnapTestSnap, SGSnapByGeneration(1),
+					types.RestoreSnapshotAction{})
+			},
+			wantAction: string(Restore),
+			check: func(t *testing.T, snap *types.StorageGroupSnap) {
+				assert.Equal(t, []string{"Restored"}, snap.State)
+			},
+		},
+		{
+			name: "set ttl",
+			call: func(c *Client) (*types.StorageGroupSnap, error) {
+				return c.SetSGSnapshotTTL(context.Background(), sgSnapTestSymID, sgSnapTestSG, sgSnapTestSnap, SGSnapByGeneration(1), 2, false)
+			},
+			wantAction: string(SetTimeToLive),
+			check: func(t *testing.T, snap *types.StorageGroupSnap) {
+				assert.Equal(t, "tomorrow", snap.TimeToLiveExpiryDate)
+			},
+		},
+		{
+			name: "secure",
+			call: func(c *Client) (*types.StorageGroupSnap, error) {
+				return c.SecureSGSnapshot(context.Background(), sgSnapTestSymID, sgSnapTestSG, sgSnapTestSnap, SGSnapByGeneration(1), 24, true)
+			},
+			wantAction: string(SetSecure),
+			check: func(t *testing.T, snap *types.StorageGroupSnap) {
+				assert.Equal(t, "tomorrow", snap.SecureExpiryDate)
+			},
+		},
+		{
+			name: "secure with invalid period",
+			call: func(c *Client) (*types.StorageGroupSnap, error) {
+				return c.SecureSGSnapshot(context.Background(), sgSnapTestSymID, sgSnapTestSG, sgSnapTestSnap, SGSnapByGeneration(1), 0, true)
+			},
+			wantErr: "secure period must be positive",
+		},
+		{
+			name: "unknown generation",
+			call: func(c *Client) (*types.StorageGroupSnap, error) {
+				return c.RestoreSGSnapshot(context.Background(), sgSnapTestSymID, sgSnapTestSG, sgSnapTestSnap, SGSnapByGeneration(9),
+					types.RestoreSnapshotAction{})
+			},
+			wantErr: "no snap with generation 9",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			MAXSnapStateRetryCount = tc.retries
+			array := newFakeSGSnapArray(tc.pendingPolls)
+			c := newSGSnapTestClient(t, array)
+			snap, err := tc.call(c)
+			if tc.wantAction != "" {
+				assert.Equal(t, []string{tc.wantAction}, array.actions)
+			} else {
+				assert.Empty(t, array.actions)
+			}
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			if tc.check != nil {
+				tc.check(t, snap)
+			}
+		})
+	}
+}
+
+func TestSGSnapLinked(t *testing.T) {
+	namesOnly := &types.StorageGroupSnap{LinkedStorageGroupNames: []string{"target_sg"}}
+	copying := &types.StorageGroupSnap{
+		LinkedStorageGroupNames: []string{"target_sg"},
+		LinkedStorageGroups:     []types.LinkedStorageGroup{{Name: "target_sg", Defined: true, PercentageCopied: 40}},
+	}
+	copied := &types.StorageGroupSnap{
+		LinkedStorageGroupNames: []string{"target_sg"},
+		LinkedStorageGroups:     []types.LinkedStorageGroup{{Name: "target_sg", Defined: true, PercentageCopied: 100}},
+	}
+	tests := []struct {
+		name   string
+		snap   *types.StorageGroupSnap
+		target string
+		copy   bool
+		done   bool
+	}{
+		{"names only", namesOnly, "target_sg", false, true},
+		{"names only with copy", namesOnly, "target_sg", true, false},
+		{"copying", copying, "target_sg", true, false},
+		{"copying without copy", copying, "target_sg", false, true},
+		{"copied", copied, "target_sg", true, true},
+		{"other target", copied, "other_sg", false, false},
+	}
+	for _, tt := range tests {
+		done, err := sgSnapLinked(tt.target, tt.copy)(tt.snap)
+		assert.NoError(t, err, tt.name)
+		assert.Equal(t, tt.done, done, tt.name)
+	}
+}
@@ -0,0 +1,256 @@
+/*
+ Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pmax
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+	log "github.com/sirupsen/logrus"
+)
+
+// Service level compliance states of a storage group
+const (
+	SLOComplianceStable   = "STABLE"
+	SLOComplianceMarginal = "MARGINAL"
+	SLOComplianceCritical = "CRITICAL"
+)
+
+// SLOMetricsWindow is how far back from the last available sample response times are averaged.
+var SLOMetricsWindow = time.Hour
+
+// sloComplianceSeverity orders compliance states from best to worst; unknown states rank as stable.
+var sloComplianceSeverity = map[string]int{
+	SLOComplianceStable:   0,
+	SLOComplianceMarginal: 1,
+	SLOComplianceCritical: 2,
+}
+
+// SLOComplianceStatus is the service level compliance of a storage group with its recent response times.
+type SLOComplianceStatus struct {
+	SymmetrixID    string
+	StorageGroupID string
+	ServiceLevel   string
+	// Compliance is SLOComplianceStable, SLOComplianceMarginal or SLOComplianceCritical, as reported by the array.
+	Compliance string
+	// ReadResponseTime and WriteResponseTime are in milliseconds, averaged over SLOMetricsWindow.
+	ReadResponseTime  float64
+	WriteResponseTime float64
+	// MetricsKnown is false if no performance data was available for the storage group.
+	MetricsKnown bool
+	Time         time.Time
+}
+
+// SLOComplianceEvent is a change in the compliance of a storage group between two polls of an SLOMonitor.
+type SLOComplianceEvent struct {
+	// Previous is the zero value the first time a storage group is seen.
+	Previous SLOComplianceStatus
+	Current  SLOComplianceStatus
+}
+
+// Degraded reports whether the storage group is less compliant than before.
+func (e SLOComplianceEvent) Degraded() bool {
+	return sloComplianceSeverity[e.Current.Compliance] > sloComplianceSeverity[e.Previous.Compliance]
+}
+
+// SLOComplianceResult holds the compliance found by GetSLOCompliance and the storage groups which could not be read.
+type SLOComplianceResult struct {
+	Statuses []SLOComplianceStatus
+	// Errors maps storage groups which could not be read to the error.
+	Errors map[string]string
+}
+
+// err joins the errors of the storage groups which could not be read, or returns nil.
+func (r *SLOComplianceResult) err() error {
+	sgIDs := make([]string, 0, len(r.Errors))
+	for sgID := range r.Errors {
+		sgIDs = append(sgIDs, sgID)
+	}
+	sort.Strings(sgIDs)
+	errs := make([]error, 0, len(sgIDs))
+	for _, sgID := range sgIDs {
+		errs = append(errs, fmt.Errorf("storage group %s: %s", sgID, r.Errors[sgID]))
+	}
+	return errors.Join(errs...)
+}
+
+// getSLOResponseTimes returns the average read and write response times of each storage group with performance data.
+func (c *Client) getSLOResponseTimes(ctx context.Context, symID string, storageGroupIDs []string) map[string][2]float64 {
+	responseTimes := make(map[string][2]float64)
+	available, err := c.getStorageGroupPerfInfos(ctx, symID)
+	if err != nil {
+		log.Warnf("no storage group response times for %s: %s", symID, err.Error())
+		return responseTimes
+	}
+	var mu sync.Mutex
+	if err := runBounded(ctx, DefaultRequestConcurrency, len(storageGroupIDs), func(i int) {
+		info, ok := available[storageGroupIDs[i]]
+		if !ok {
+			return
+		}
+		averages, _, err := averageWindowMetrics(info.FirstAvailableDate, info.LastAvailableDate, SLOMetricsWindow,
+			func(start, end int64) ([]types.StorageGroupMetric, error) {
+				metrics, err := c.GetStorageGroupMetrics(ctx, symID, info.StorageGroupID, []string{"ReadResponseTime", "WriteResponseTime"}, start, end)
+				if err != nil {
+					return nil, err
+				}
+				return metrics.ResultList.Result, nil
+			},
+			func(metric types.StorageGroupMetric) float64 { return metric.ReadResponseTime },
+			func(metric types.StorageGroupMetric) float64 { return metric.WriteResponseTime },
+		)
+		if err != nil {
+			log.Warnf("no response times for storage group %s: %s", info.StorageGroupID, err.Error())
+			return
+		}
+		if averages == nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		responseTimes[info.StorageGroupID] = [2]float64{averages[0], averages[1]}
+	}); err != nil {
+		log.Warnf("response times for %s incomplete: %s", symID, err.Error())
+	}
+	return responseTimes
+}
+
+// GetSLOCompliance returns the service level compliance and recent response times of the storage groups of
+// an array which have one of the given service levels, or any service level if none are given.
+// Storage groups without a service level are not included. Storage groups which cannot be read are recorded
+// in the Errors of the result, and the others are still returned.
+func (c *Client) GetSLOCompliance(ctx context.Context, symID string, serviceLevels []string) (*SLOComplianceResult, error) {
+	defer c.TimeSpent("GetSLOCompliance", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	sgIDList, err := c.GetStorageGroupIDList(ctx, symID, "", false)
+	if err != nil {
+		return nil, err
+	}
+	result := &SLOComplianceResult{
+		Statuses: make([]SLOComplianceStatus, 0),
+		Errors:   make(map[string]string),
+	}
+	var mu sync.Mutex
+	if err := runBounded(ctx, DefaultRequestConcurrency, len(sgIDList.StorageGroupIDs), func(i int) {
+		sg, err := c.GetStorageGroup(ctx, symID, sgIDList.StorageGroupIDs[i])
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			log.Errorf("GetSLOCompliance failed for storage group %s: %s", sgIDList.StorageGroupIDs[i], err.Error())
+			result.Errors[sgIDList.StorageGroupIDs[i]] = err.Error()
+			return
+		}
+		serviceLevel := storageGroupServiceLevel(sg)
+		if serviceLevel == "None" || (len(serviceLevels) > 0 && !stringInSlice(serviceLevel, serviceLevels)) {
+			return
+		}
+		result.Statuses = append(result.Statuses, SLOComplianceStatus{
+			SymmetrixID:    symID,
+			StorageGroupID: sg.StorageGroupID,
+			ServiceLevel:   serviceLevel,
+			Compliance:     sg.SLOCompliance,
+			Time:           time.Now(),
+		})
+	}); err != nil {
+		return nil, err
+	}
+	statuses := result.Statuses
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].StorageGroupID < statuses[j].StorageGroupID })
+
+	storageGroupIDs := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		storageGroupIDs = append(storageGroupIDs, status.StorageGroupID)
+	}
+	responseTimes := c.getSLOResponseTimes(ctx, symID, storageGroupIDs)
+	for i := range statuses {
+		if times, ok := responseTimes[statuses[i].StorageGroupID]; ok {
+			statuses[i].ReadResponseTime = times[0]
+			statuses[i].WriteResponseTime = times[1]
+			statuses[i].MetricsKnown = true
+		}
+	}
+	return result, nil
+}
+
+// SLOMonitor polls the service level compliance of storage groups and reports compliance transitions.
+type SLOMonitor struct {
+	Client      Pmax
+	SymmetrixID string
+	// ServiceLevels limits the monitored storage groups, e.g. to Diamond and Gold; all are monitored if empty.
+	ServiceLevels []string
+	// OnTransition is called for each storage group whose compliance changed since the previous poll. The first
+	// time a storage group is seen it is only reported if it is not SLOComplianceStable.
+	OnTransition func(SLOComplianceEvent)
+
+	mu   sync.Mutex
+	last map[string]SLOComplianceStatus
+}
+
+// Poll gets the compliance of the monitored storage groups, calls OnTransition for each transition and returns them.
+// Storage groups which are no longer monitored are forgotten. If some storage groups could not be read, the
+// transitions of the others are still reported and the failures are returned as the error.
+func (m *SLOMonitor) Poll(ctx context.Context) ([]SLOComplianceEvent, error) {
+	result, err := m.Client.GetSLOCompliance(ctx, m.SymmetrixID, m.ServiceLevels)
+	if err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	current := make(map[string]SLOComplianceStatus, len(result.Statuses))
+	events := make([]SLOComplianceEvent, 0)
+	for _, status := range result.Statuses {
+		previous, seen := m.last[status.StorageGroupID]
+		current[status.StorageGroupID] = status
+		if (seen && previous.Compliance != status.Compliance) || (!seen && status.Compliance != SLOComplianceStable) {
+			events = append(events, SLOComplianceEvent{Previous: previous, Current: status})
+		}
+	}
+	// storage groups which could not be read keep their last status, so they are not reported as new next time
+	for sgID := range result.Errors {
+		if previous, seen := m.last[sgID]; seen {
+			current[sgID] = previous
+		}
+	}
+	m.last = current
+	m.mu.Unlock()
+	if m.OnTransition != nil {
+		for _, event := range events {
+			m.OnTransition(event)
+		}
+	}
+	return events, result.err()
+}
+
+// Run polls every interval until ctx is done, and returns ctx.Err(). Polling errors are logged.
+func (m *SLOMonitor) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := m.Poll(ctx); err != nil {
+			log.Errorf("SLO compliance poll of %s failed: %s", m.SymmetrixID, err.Error())
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
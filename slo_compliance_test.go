@@ -0,0 +1,203 @@
+/*
+ *
+ * Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pmax
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+	"github.com/stretchr/testify/assert"
+)
+
+// newSLOComplianceServer serves sg_none (without a service level) and the storage groups in compliance, with
+// their compliance, and response times for sg_diamond only. Reading a storage group whose compliance is
+// "FAIL" fails.
+func newSLOComplianceServer(mu *sync.Mutex, compliance map[string]string) *httptest.Server {
+	serviceLevels := map[string]string{"sg_diamond": "Diamond", "sg_gold": "Gold", "sg_none": "None"}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		path := r.URL.Path
+		last := path[strings.LastIndex(path, "/")+1:]
+		var out interface{}
+		switch {
+		case strings.HasSuffix(path, XStorageGroup):
+			sgIDs := []string{"sg_none"}
+			for sgID := range compliance {
+				sgIDs = append(sgIDs, sgID)
+			}
+			out = &types.StorageGroupIDList{StorageGroupIDs: sgIDs}
+		case strings.Contains(path, XStorageGroup+"/") && compliance[last] == "FAIL":
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"message":"storage group unavailable"}`))
+			return
+		case strings.Contains(path, XStorageGroup+"/") && serviceLevels[last] != "":
+			out = &types.StorageGroup{StorageGroupID: last, SLO: serviceLevels[last], SLOCompliance: compliance[last]}
+		case strings.HasSuffix(path, StorageGroup+Keys):
+			out = &types.StorageGroupKeysResult{StorageGroupInfos: []types.StorageGroupInfo{
+				{StorageGroupID: "sg_diamond", FirstAvailableDate: 0, LastAvailableDate: 3600000},
+			}}
+		case strings.HasSuffix(path, StorageGroup+Metrics):
+			out = &types.StorageGroupMetricsIterator{ResultList: types.StorageGroupMetricsResultList{Result: []types.StorageGroupMetric{
+				{ReadResponseTime: 1, WriteResponseTime: 2},
+				{ReadResponseTime: 3, WriteResponseTime: 4},
+			}}}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"not found"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(out)
+	}))
+}
+
+func TestGetSLOCompliance(t *testing.T) {
+	symID := "000000000001"
+	var mu sync.Mutex
+	compliance := map[string]string{"sg_diamond": SLOComplianceMarginal, "sg_gold": SLOComplianceStable}
+	server := newSLOComplianceServer(&mu, compliance)
+	defer server.Close()
+	c, err := NewClientWithArgs(server.URL, "", true, true, "")
+	assert.NoError(t, err)
+	c.SetAllowedArrays([]string{symID})
+
+	result, err := c.GetSLOCompliance(context.Background(), symID, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, result.Errors)
+	statuses := result.Statuses
+	assert.Len(t, statuses, 2)
+	assert.Equal(t, "sg_diamond", statuses[0].StorageGroupID)
+	assert.Equal(t, "Diamond", statuses[0].ServiceLevel)
+	assert.Equal(t, SLOComplianceMarginal, statuses[0].Compliance)
+	assert.True(t, statuses[0].MetricsKnown)
+	assert.Equal(t, 2.0, statuses[0].ReadResponseTime)
+	assert.Equal(t, 3.0, statuses[0].WriteResponseTime)
+	assert.Equal(t, "sg_gold", statuses[1].StorageGroupID)
+	assert.False(t, statuses[1].MetricsKnown)
+
+	result, err = c.GetSLOCompliance(context.Background(), symID, []string{"Gold"})
+	assert.NoError(t, err)
+	assert.Len(t, result.Statuses, 1)
+	assert.Equal(t, "sg_gold", result.Statuses[0].StorageGroupID)
+
+	// a storage group which cannot be read does not hide the others
+	mu.Lock()
+	compliance["sg_diamond"] = "FAIL"
+	mu.Unlock()
+	result, err = c.GetSLOCompliance(context.Background(), symID, nil)
+	assert.NoError(t, err)
+	assert.Len(t, result.Statuses, 1)
+	assert.Equal(t, "sg_gold", result.Statuses[0].StorageGroupID)
+	assert.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors["sg_diamond"], "storage group unavailable")
+
+	_, err = c.GetSLOCompliance(context.Background(), "000000000002", nil)
+	assert.Error(t, err)
+}
+
+func TestSLOMonitor(t *testing.T) {
+	symID := "000000000001"
+	var mu sync.Mutex
+	compliance := map[string]string{"sg_diamond": SLOComplianceStable, "sg_gold": SLOComplianceMarginal}
+	server := newSLOComplianceServer(&mu, compliance)
+	defer server.Close()
+	c, err := NewClientWithArgs(server.URL, "", true, true, "")
+	assert.NoError(t, err)
+	c.SetAllowedArrays([]string{symID})
+
+	hooked := make([]SLOComplianceEvent, 0)
+	monitor := &SLOMonitor{
+		Client:       c,
+		SymmetrixID:  symID,
+		OnTransition: func(event SLOComplianceEvent) { hooked = append(hooked, event) },
+	}
+
+	// only the non-stable storage group is reported on the first poll
+	events, err := monitor.Poll(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, "sg_gold", events[0].Current.StorageGroupID)
+	assert.Equal(t, "", events[0].Previous.Compliance)
+	assert.True(t, events[0].Degraded())
+
+	events, err = monitor.Poll(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+
+	mu.Lock()
+	compliance["sg_diamond"] = SLOComplianceCritical
+	compliance["sg_gold"] = SLOComplianceStable
+	mu.Unlock()
+	events, err = monitor.Poll(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, SLOComplianceStable, events[0].Previous.Compliance)
+	assert.Equal(t, SLOComplianceCritical, events[0].Current.Compliance)
+	assert.True(t, events[0].Current.MetricsKnown)
+	assert.True(t, events[0].Degraded())
+	assert.False(t, events[1].Degraded())
+	assert.Len(t, hooked, 3)
+
+	// a storage group which cannot be read keeps its status and the others are still reported
+	mu.Lock()
+	compliance["sg_diamond"] = "FAIL"
+	compliance["sg_gold"] = SLOComplianceMarginal
+	mu.Unlock()
+	events, err = monitor.Poll(context.Background())
+	assert.ErrorContains(t, err, "storage group sg_diamond")
+	assert.Len(t, events, 1)
+	assert.Equal(t, "sg_gold", events[0].Current.StorageGroupID)
+
+	mu.Lock()
+	compliance["sg_diamond"] = SLOComplianceCritical
+	mu.Unlock()
+	events, err = monitor.Poll(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+
+	// a storage group which is gone is forgotten, and reported as new if it comes back
+	mu.Lock()
+	delete(compliance, "sg_gold")
+	mu.Unlock()
+	events, err = monitor.Poll(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+	monitor.mu.Lock()
+	assert.NotContains(t, monitor.last, "sg_gold")
+	monitor.mu.Unlock()
+	mu.Lock()
+	compliance["sg_gold"] = SLOComplianceMarginal
+	mu.Unlock()
+	events, err = monitor.Poll(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, "", events[0].Previous.Compliance)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, monitor.Run(ctx, time.Hour), context.Canceled)
+}
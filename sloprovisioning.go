@@ -64,6 +64,10 @@ const (
 	SelectVolHostPaths         = "volume_host_paths,"
 	SelectSRP                  = "srp,"
 	SelectNumberOfMaskingViews = "num_of_masking_views"
+	SelectWWN                  = "wwn,"
+	SelectEffectiveWWN         = "effective_wwn,"
+	SelectEncapsulatedWWN      = "encapsulated_wwn,"
+	SelectNGUID                = "nguid"
 	FilterIdentifier           = "&filter=identifier EQ "
 	XPortGroupEnhance          = "/port-groups"
 	SelectID                   = "id,"
@@ -137,7 +141,7 @@ func (c *Client) GetVolumeIDsIteratorWithParams(ctx context.Context, symID strin
 			for _, subVal := range strings.Split(val, ",") {
 				// if value starts with > or <, directly add it
 				if regexp.MustCompile("^[><]\\d+(\\.\\d+)?$").MatchString(subVal) {
-					query += fmt.Sprintf("%s%s", key, val)
+					query += fmt.Sprintf("%s%s", key, subVal)
 				} else {
 					// remove the first '='
 					if subVal[0] == '=' {
@@ -389,43 +393,11 @@ func (c *Client) GetVolumesByIdentifierMatch(ctx context.Context, symID string,
 		"&filter=identifier%20like%20" + identifierMatcher +
 		"&limit=100&expiration_delay_secs=30"
 
-	allVolumes := make([]types.VolumeEnhanced, 0)
-	requestURL := baseURL
-
-	for {
-		ctx, cancel := c.GetTimeoutContext(ctx)
-		resp, err := c.api.DoAndGetResponseBody(
-			ctx, http.MethodGet, requestURL, c.getDefaultHeaders(), nil)
-		if err != nil {
-			cancel()
-			log.Error("GetVolume info failed: " + err.Error())
-			return nil, err
-		}
-		if err = c.checkResponse(resp); err != nil {
-			cancel()
-			return nil, err
-		}
-		page := &types.Volumev1{}
-		decoder := json.NewDecoder(resp.Body)
-		if err = decoder.Decode(page); err != nil {
-			resp.Body.Close()
-			cancel()
-			return nil, err
-		}
-
-		resp.Body.Close()
-		cancel()
-
-		log.Debugf("Page remaining %d, out of total %d", page.VolumePaging.RemainingInstances, page.VolumePaging.TotalInstances)
-		if page.Volumes != nil {
-			allVolumes = append(allVolumes, page.Volumes...)
-		}
-		if page.VolumePaging.RemainingInstances == 0 {
-			break
-		}
-		requestURL = baseURL + "&resume_token=" + page.VolumePaging.ResumeToken
+	allVolumes, err := c.getVolumesV1(ctx, baseURL)
+	if err != nil {
+		log.Error("GetVolume info failed: " + err.Error())
+		return nil, err
 	}
-
 	return &types.Volumev1{Volumes: allVolumes}, nil
 }
 
@@ -445,16 +417,25 @@ func (c *Client) GetVolumesCapacityBulk(ctx context.Context, symID string) (*typ
 		SelectEffectiveUsedCapGB + SelectStorageGroupID + SelectSRPID +
 		"&limit=1000&expiration_delay_secs=30"
 
+	allVolumes, err := c.getVolumesV1(ctx, baseURL)
+	if err != nil {
+		log.Errorf("GetVolumesCapacityBulk failed: %s", err.Error())
+		return nil, err
+	}
+	return &types.Volumev1{Volumes: allVolumes}, nil
+}
+
+// getVolumesV1 reads every page of a v1 volumes query. baseURL is the query of the first page,
+// to which the resume token of each following page is appended.
+func (c *Client) getVolumesV1(ctx context.Context, baseURL string) ([]types.VolumeEnhanced, error) {
 	allVolumes := make([]types.VolumeEnhanced, 0)
 	requestURL := baseURL
-
 	for {
-		ctx, cancel := c.GetTimeoutContext(ctx)
+		pageCtx, cancel := c.GetTimeoutContext(ctx)
 		resp, err := c.api.DoAndGetResponseBody(
-			ctx, http.MethodGet, requestURL, c.getDefaultHeaders(), nil)
+			pageCtx, http.MethodGet, requestURL, c.getDefaultHeaders(), nil)
 		if err != nil {
 			cancel()
-			log.Errorf("GetVolumesCapacityBulk failed: %s", err.Error())
 			return nil, err
 		}
 		if err = c.checkResponse(resp); err != nil {
@@ -462,27 +443,21 @@ func (c *Client) GetVolumesCapacityBulk(ctx context.Context, symID string) (*typ
 			return nil, err
 		}
 		page := &types.Volumev1{}
-		decoder := json.NewDecoder(resp.Body)
-		if err = decoder.Decode(page); err != nil {
-			resp.Body.Close()
-			cancel()
-			return nil, err
-		}
-
+		err = json.NewDecoder(resp.Body).Decode(page)
 		resp.Body.Close()
 		cancel()
-
-		log.Debugf("GetVolumesCapacityBulk page remaining %d, out of total %d", page.VolumePaging.RemainingInstances, page.VolumePaging.TotalInstances)
-		if page.Volumes != nil {
-			allVolumes = append(allVolumes, page.Volumes...)
+		if err != nil {
+			return nil, err
 		}
+
+		log.Debugf("Page remaining %d, out of total %d", page.VolumePaging.RemainingInstances, page.VolumePaging.TotalInstances)
+		allVolumes = append(allVolumes, page.Volumes...)
 		if page.VolumePaging.RemainingInstances == 0 {
 			break
 		}
 		requestURL = baseURL + "&resume_token=" + page.VolumePaging.ResumeToken
 	}
-
-	return &types.Volumev1{Volumes: allVolumes}, nil
+	return allVolumes, nil
 }
 
 // GetStorageGroupIDList returns a list of StorageGroupIds in a StorageGroupIDList type.
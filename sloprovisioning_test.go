@@ -432,6 +432,31 @@ func TestGetVolumesCapacityBulkPagination(t *testing.T) {
 	assert.Equal(t, "0002", volumes.Volumes[1].ID)
 }
 
+func TestGetVolumeIDListWithParamsRange(t *testing.T) {
+	allowedArray := "testSymID"
+	var rawQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rawQuery = r.URL.RawQuery
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(types.VolumeIterator{
+			ResultList:  types.VolumeResultList{VolumeList: []types.VolumeIDList{{VolumeIDs: "0001"}}, From: 1, To: 1},
+			Count:       1,
+			MaxPageSize: 1000,
+		})
+	}))
+	defer server.Close()
+
+	c, err := NewClientWithArgs(server.URL, "", true, true, "")
+	assert.NoError(t, err)
+	c.SetAllowedArrays([]string{allowedArray})
+
+	// each bound of a range is sent as its own comparison, not the whole value after the first bound
+	volumeIDs, err := c.GetVolumeIDListWithParams(context.Background(), allowedArray, map[string]string{"cap_gb": ">10,<100"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0001"}, volumeIDs)
+	assert.Equal(t, "cap_gb>10&cap_gb<100", rawQuery)
+}
+
 func TestStorageGroupVolumeCounts(t *testing.T) {
 	allowedArray := "testSymID"
 	tests := []struct {
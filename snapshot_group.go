@@ -0,0 +1,190 @@
+/*
+ Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pmax
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+	log "github.com/sirupsen/logrus"
+)
+
+// ConsistentSnapshotGroupRequest describes the volumes to snapshot together.
+// Volumes of the listed storage groups are added to VolumeIDs.
+type ConsistentSnapshotGroupRequest struct {
+	SnapshotName    string
+	VolumeIDs       []string
+	StorageGroupIDs []string
+	// TimeToLive is passed through to CreateSnapshot; zero means no time to live.
+	TimeToLive int64
+}
+
+// ConsistentSnapshotGroup is a snapshot taken at the same point in time across a set of volumes.
+type ConsistentSnapshotGroup struct {
+	SymmetrixID  string
+	SnapshotName string
+	VolumeIDs    []string
+	// Timestamp is the snapshot timestamp reported by every volume.
+	Timestamp string
+	// SnapIDs maps each volume ID to the snap ID of its new generation.
+	SnapIDs map[string]int64
+}
+
+// CreateConsistentSnapshotGroup snapshots all the requested volumes in a single SnapVX activation and checks that
+// every volume received a new generation with the same timestamp. If creation or verification fails, the new
+// generations that were created are deleted again and the returned error describes both failures.
+func (c *Client) CreateConsistentSnapshotGroup(ctx context.Context, symID string, request ConsistentSnapshotGroupRequest) (*ConsistentSnapshotGroup, error) {
+	defer c.TimeSpent("CreateConsistentSnapshotGroup", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	if request.SnapshotName == "" {
+		return nil, fmt.Errorf("a snapshot name is required to create a consistent snapshot group")
+	}
+	volumeIDs := make([]string, 0, len(request.VolumeIDs))
+	for _, volumeID := range request.VolumeIDs {
+		if !stringInSlice(volumeID, volumeIDs) {
+			volumeIDs = append(volumeIDs, volumeID)
+		}
+	}
+	for _, storageGroupID := range request.StorageGroupIDs {
+		sgVolumeIDs, err := c.GetVolumeIDListInStorageGroup(ctx, symID, storageGroupID)
+		if err != nil {
+			return nil, err
+		}
+		for _, volumeID := range sgVolumeIDs {
+			if !stringInSlice(volumeID, volumeIDs) {
+				volumeIDs = append(volumeIDs, volumeID)
+			}
+		}
+	}
+	if len(volumeIDs) == 0 {
+		return nil, fmt.Errorf("no volumes to snapshot for consistent snapshot group %s", request.SnapshotName)
+	}
+	sort.Strings(volumeIDs)
+
+	// Remember how many generations of the snapshot each volume had, so that after a failure
+	// only the generations this call created are rolled back.
+	before := make(map[string]int, len(volumeIDs))
+	for _, volumeID := range volumeIDs {
+		sources, err := c.getVolumeSnapshotSources(ctx, symID, volumeID, request.SnapshotName)
+		if err != nil {
+			return nil, err
+		}
+		before[volumeID] = len(sources)
+	}
+
+	sourceVolumes := make([]types.VolumeList, 0, len(volumeIDs))
+	for _, volumeID := range volumeIDs {
+		sourceVolumes = append(sourceVolumes, types.VolumeList{Name: volumeID})
+	}
+	if err := c.CreateSnapshot(ctx, symID, request.SnapshotName, sourceVolumes, request.TimeToLive); err != nil {
+		return nil, c.rollbackConsistentSnapshotGroup(ctx, symID, request.SnapshotName, before, err)
+	}
+
+	group := &ConsistentSnapshotGroup{
+		SymmetrixID:  symID,
+		SnapshotName: request.SnapshotName,
+		VolumeIDs:    volumeIDs,
+		SnapIDs:      make(map[string]int64, len(volumeIDs)),
+	}
+	var groupTime time.Time
+	for _, volumeID := range volumeIDs {
+		sources, err := c.getVolumeSnapshotSources(ctx, symID, volumeID, request.SnapshotName)
+		if err != nil {
+			return nil, c.rollbackConsistentSnapshotGroup(ctx, symID, request.SnapshotName, before, err)
+		}
+		if len(sources) <= before[volumeID] {
+			err = fmt.Errorf("volume %s did not receive a new generation of snapshot %s", volumeID, request.SnapshotName)
+			return nil, c.rollbackConsistentSnapshotGroup(ctx, symID, request.SnapshotName, before, err)
+		}
+		newest := sources[0]
+		newestTime := parseSnapTimestamp(newest.TimeStamp)
+		switch {
+		case group.Timestamp == "":
+			group.Timestamp = newest.TimeStamp
+			groupTime = newestTime
+		case !sameSnapTimestamp(group.Timestamp, groupTime, newest.TimeStamp, newestTime):
+			err = fmt.Errorf("volume %s snapshot %s timestamp %s does not match group timestamp %s",
+				volumeID, request.SnapshotName, newest.TimeStamp, group.Timestamp)
+			return nil, c.rollbackConsistentSnapshotGroup(ctx, symID, request.SnapshotName, before, err)
+		}
+		group.SnapIDs[volumeID] = newest.SnapID
+	}
+	log.Infof("Created consistent snapshot group %s of %d volumes at %s", request.SnapshotName, len(volumeIDs), group.Timestamp)
+	return group, nil
+}
+
+// getVolumeSnapshotSources returns the generations of snapshotName on a volume, newest first.
+func (c *Client) getVolumeSnapshotSources(ctx context.Context, symID, volumeID, snapshotName string) ([]types.VolumeSnapshotSource, error) {
+	snapInfo, err := c.GetVolumeSnapInfo(ctx, symID, volumeID)
+	if err != nil {
+		return nil, err
+	}
+	sources := make([]types.VolumeSnapshotSource, 0)
+	for _, src := range snapInfo.VolumeSnapshotSource {
+		if src.SnapshotName == snapshotName {
+			sources = append(sources, src)
+		}
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		return sources[i].Generation < sources[j].Generation
+	})
+	return sources, nil
+}
+
+// rollbackConsistentSnapshotGroup deletes generation 0 of the snapshot from every volume that gained a generation
+// since before was recorded, and returns cause annotated with the outcome of the rollback.
+func (c *Client) rollbackConsistentSnapshotGroup(ctx context.Context, symID, snapshotName string, before map[string]int, cause error) error {
+	volumeIDs := make([]string, 0, len(before))
+	for volumeID := range before {
+		volumeIDs = append(volumeIDs, volumeID)
+	}
+	sort.Strings(volumeIDs)
+	rolledBack := make([]string, 0)
+	failures := make([]string, 0)
+	for _, volumeID := range volumeIDs {
+		sources, err := c.getVolumeSnapshotSources(ctx, symID, volumeID, snapshotName)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", volumeID, err.Error()))
+			continue
+		}
+		if len(sources) <= before[volumeID] {
+			continue
+		}
+		if err := c.DeleteSnapshotS(ctx, symID, snapshotName, []types.VolumeList{{Name: volumeID}}, 0); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", volumeID, err.Error()))
+			continue
+		}
+		rolledBack = append(rolledBack, volumeID)
+	}
+	log.Errorf("consistent snapshot group %s failed: %s; rolled back %v", snapshotName, cause.Error(), rolledBack)
+	if len(failures) > 0 {
+		return fmt.Errorf("consistent snapshot group %s failed: %w; rollback failed for %s", snapshotName, cause, strings.Join(failures, ", "))
+	}
+	return fmt.Errorf("consistent snapshot group %s failed: %w; rolled back %d volumes", snapshotName, cause, len(rolledBack))
+}
+
+// sameSnapTimestamp compares two snapshot timestamps, as times when both parse and as strings otherwise.
+func sameSnapTimestamp(a string, aTime time.Time, b string, bTime time.Time) bool {
+	if !aTime.IsZero() && !bTime.IsZero() {
+		return aTime.Equal(bTime)
+	}
+	return a == b
+}
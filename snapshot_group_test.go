@@ -0,0 +1,191 @@
+/*
+ *
+ * Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pmax
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSnapshotGroupArray keeps per volume snapshot generations for CreateConsistentSnapshotGroup tests.
+type fakeSnapshotGroupArray struct {
+	mu        sync.Mutex
+	sources   map[string][]types.VolumeSnapshotSource
+	sgVolumes map[string][]string
+	// partialVolumes, when set, are the only volumes snapped before the create fails.
+	partialVolumes []string
+	// skewVolume receives a different timestamp from the rest of the group.
+	skewVolume string
+	deletes    []string
+	nextSnapID int64
+}
+
+func (f *fakeSnapshotGroupArray) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	path := r.URL.Path
+	last := path[strings.LastIndex(path, "/")+1:]
+	switch {
+	case strings.Contains(path, "sloprovisioning") && r.Method == http.MethodGet:
+		volumeIDs := f.sgVolumes[r.URL.Query().Get("storageGroupId")]
+		iter := &types.VolumeIterator{Count: len(volumeIDs), MaxPageSize: 1000, ResultList: types.VolumeResultList{From: 1, To: len(volumeIDs)}}
+		for _, volumeID := range volumeIDs {
+			iter.ResultList.VolumeList = append(iter.ResultList.VolumeList, types.VolumeIDList{VolumeIDs: volumeID})
+		}
+		_ = json.NewEncoder(w).Encode(iter)
+	case strings.HasSuffix(path, XSnapshot):
+		volumeID := strings.TrimSuffix(path, XSnapshot)
+		volumeID = volumeID[strings.LastIndex(volumeID, "/")+1:]
+		_ = json.NewEncoder(w).Encode(&types.SnapshotVolumeGeneration{DeviceName: volumeID, VolumeSnapshotSource: f.sources[volumeID]})
+	case r.Method == http.MethodPost:
+		payload := &types.CreateVolumesSnapshot{}
+		_ = json.NewDecoder(r.Body).Decode(payload)
+		for _, vol := range payload.SourceVolumeList {
+			if f.partialVolumes != nil && !stringInSlice(vol.Name, f.partialVolumes) {
+				continue
+			}
+			stamp := "Mon Nov 14 09:58:01 2022"
+			if vol.Name == f.skewVolume {
+				stamp = "Mon Nov 14 09:58:02 2022"
+			}
+			f.nextSnapID++
+			f.addGeneration(vol.Name, last, stamp, f.nextSnapID)
+		}
+		if f.partialVolumes != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"message":"induced error"}`))
+		}
+	case r.Method == http.MethodDelete:
+		payload := &types.DeleteVolumeSnapshot{}
+		_ = json.NewDecoder(r.Body).Decode(payload)
+		for _, vol := range payload.DeviceNameListSource {
+			f.deletes = append(f.deletes, vol.Name)
+			kept := make([]types.VolumeSnapshotSource, 0)
+			for _, src := range f.sources[vol.Name] {
+				if src.SnapshotName != last {
+					kept = append(kept, src)
+				} else if src.Generation != payload.Generation {
+					if src.Generation > payload.Generation {
+						src.Generation--
+					}
+					kept = append(kept, src)
+				}
+			}
+			f.sources[vol.Name] = kept
+		}
+	}
+}
+
+func (f *fakeSnapshotGroupArray) addGeneration(volumeID, name, stamp string, snapID int64) {
+	for i := range f.sources[volumeID] {
+		if f.sources[volumeID][i].SnapshotName == name {
+			f.sources[volumeID][i].Generation++
+		}
+	}
+	f.sources[volumeID] = append(f.sources[volumeID], types.VolumeSnapshotSource{
+		SnapshotName: name, Generation: 0, SnapID: snapID, TimeStamp: stamp,
+	})
+}
+
+func TestCreateConsistentSnapshotGroup(t *testing.T) {
+	symID := "000000000001"
+	tests := []struct {
+		name            string
+		request         ConsistentSnapshotGroupRequest
+		partialVolumes  []string
+		skewVolume      string
+		wantVolumes     []string
+		wantErr         string
+		wantDeletes     []string
+		wantGenerations map[string]int
+	}{
+		{
+			name:            "volumes and storage groups",
+			request:         ConsistentSnapshotGroupRequest{SnapshotName: "cg", VolumeIDs: []string{"00002", "00001"}, StorageGroupIDs: []string{"sg_1"}},
+			wantVolumes:     []string{"00001", "00002", "00003"},
+			wantGenerations: map[string]int{"00001": 2, "00002": 1, "00003": 1},
+		},
+		{
+			name:            "partial create is rolled back",
+			request:         ConsistentSnapshotGroupRequest{SnapshotName: "cg", VolumeIDs: []string{"00001", "00002"}},
+			partialVolumes:  []string{"00002"},
+			wantErr:         "rolled back 1 volumes",
+			wantDeletes:     []string{"00002"},
+			wantGenerations: map[string]int{"00001": 1, "00002": 0},
+		},
+		{
+			name:            "timestamp mismatch is rolled back",
+			request:         ConsistentSnapshotGroupRequest{SnapshotName: "cg", VolumeIDs: []string{"00001", "00002"}},
+			skewVolume:      "00002",
+			wantErr:         "does not match group timestamp",
+			wantDeletes:     []string{"00001", "00002"},
+			wantGenerations: map[string]int{"00001": 1, "00002": 0},
+		},
+		{
+			name:    "no snapshot name",
+			request: ConsistentSnapshotGroupRequest{VolumeIDs: []string{"00001"}},
+			wantErr: "snapshot name is required",
+		},
+		{
+			name:    "no volumes",
+			request: ConsistentSnapshotGroupRequest{SnapshotName: "cg", StorageGroupIDs: []string{"empty"}},
+			wantErr: "no volumes to snapshot",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			array := &fakeSnapshotGroupArray{
+				sources: map[string][]types.VolumeSnapshotSource{
+					"00001": {{SnapshotName: "cg", Generation: 0, SnapID: 100, TimeStamp: "Sun Nov 13 09:00:00 2022"}},
+				},
+				sgVolumes:      map[string][]string{"sg_1": {"00003", "00001"}},
+				partialVolumes: tc.partialVolumes,
+				skewVolume:     tc.skewVolume,
+			}
+			server := httptest.NewServer(array)
+			defer server.Close()
+			c, err := NewClientWithArgs(server.URL, "", true, true, "")
+			assert.NoError(t, err)
+			c.SetAllowedArrays([]string{symID})
+
+			group, err := c.CreateConsistentSnapshotGroup(context.Background(), symID, tc.request)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.wantVolumes, group.VolumeIDs)
+				assert.Equal(t, "Mon Nov 14 09:58:01 2022", group.Timestamp)
+				assert.Len(t, group.SnapIDs, len(tc.wantVolumes))
+			}
+			assert.ElementsMatch(t, tc.wantDeletes, array.deletes)
+			for volumeID, want := range tc.wantGenerations {
+				assert.Len(t, array.sources[volumeID], want, volumeID)
+			}
+		})
+	}
+}
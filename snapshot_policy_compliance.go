@@ -0,0 +1,205 @@
+/*
+ Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pmax
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+	log "github.com/sirupsen/logrus"
+)
+
+// Snapshot policy compliance states
+const (
+	SnapshotPolicyComplianceNormal   = "Normal"
+	SnapshotPolicyComplianceWarning  = "Warning"
+	SnapshotPolicyComplianceCritical = "Critical"
+)
+
+// SnapshotPolicyCompliance is the compliance of one storage group against one snapshot policy.
+type SnapshotPolicyCompliance struct {
+	SymmetrixID      string
+	SnapshotPolicyID string
+	StorageGroupID   string
+	// Expected is the number of snapshots the policy maintains.
+	Expected int64
+	// Good is the number of snapshots which are neither failed nor expired.
+	Good int64
+	// Failed is the number of failed or expired snapshots.
+	Failed int64
+	// Missing is the number of expected snapshots which do not exist.
+	Missing int64
+	// LastSuccess is the time of the newest good snapshot, zero if there is none.
+	LastSuccess time.Time
+	// State is SnapshotPolicyComplianceNormal, SnapshotPolicyComplianceWarning or SnapshotPolicyComplianceCritical.
+	State string
+	// ArrayCompliance is the compliance as reported by Unisphere, if any.
+	ArrayCompliance string
+	Suspended       bool
+}
+
+// SnapshotPolicyComplianceSummary summarises snapshot policy compliance across an array.
+type SnapshotPolicyComplianceSummary struct {
+	SymmetrixID string
+	Policies    int
+	Normal      int
+	Warning     int
+	Critical    int
+	Compliance  []SnapshotPolicyCompliance
+	// Errors maps "policy/storage group" (or just the policy) to the error met while evaluating it.
+	Errors map[string]string
+}
+
+// snapshotPolicyState computes the compliance state of good snapshots against the policy thresholds.
+// A threshold of zero or less is disabled.
+func snapshotPolicyState(policy *types.SnapshotPolicy, good int64) string {
+	if policy.ComplianceCountCritical > 0 && good <= policy.ComplianceCountCritical {
+		return SnapshotPolicyComplianceCritical
+	}
+	if policy.ComplianceCountWarning > 0 && good <= policy.ComplianceCountWarning {
+		return SnapshotPolicyComplianceWarning
+	}
+	return SnapshotPolicyComplianceNormal
+}
+
+func sgSnapBad(snap *types.StorageGroupSnap) bool {
+	if snap.Expired {
+		return true
+	}
+	for _, state := range snap.State {
+		s := strings.ToLower(state)
+		if strings.Contains(s, "fail") || strings.Contains(s, "invalid") {
+			return true
+		}
+	}
+	return false
+}
+
+// GetSnapshotPolicyCompliance returns the compliance of a storage group against a snapshot policy,
+// computed from the snapshots the policy has taken of the storage group.
+func (c *Client) GetSnapshotPolicyCompliance(ctx context.Context, symID, snapshotPolicyID, storageGroupID string) (*SnapshotPolicyCompliance, error) {
+	defer c.TimeSpent("GetSnapshotPolicyCompliance", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	policy, err := c.GetSnapshotPolicy(ctx, symID, snapshotPolicyID)
+	if err != nil {
+		return nil, err
+	}
+	return c.getSnapshotPolicyCompliance(ctx, symID, policy, storageGroupID)
+}
+
+func (c *Client) getSnapshotPolicyCompliance(ctx context.Context, symID string, policy *types.SnapshotPolicy, storageGroupID string) (*SnapshotPolicyCompliance, error) {
+	compliance := &SnapshotPolicyCompliance{
+		SymmetrixID:      symID,
+		SnapshotPolicyID: policy.SnapshotPolicyName,
+		StorageGroupID:   storageGroupID,
+		Expected:         policy.SnapshotCount,
+		Suspended:        policy.Suspended,
+	}
+	sgPolicy, err := c.GetStorageGroupSnapshotPolicy(ctx, symID, policy.SnapshotPolicyName, storageGroupID)
+	if err != nil {
+		return nil, err
+	}
+	compliance.ArrayCompliance = sgPolicy.Compliance
+	compliance.Suspended = compliance.Suspended || sgPolicy.Suspended
+
+	sgSnapshots, err := c.GetStorageGroupSnapshots(ctx, symID, storageGroupID, true, false)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range sgSnapshots.SlSnapshotName {
+		if !strings.EqualFold(name, policy.SnapshotPolicyName) {
+			continue
+		}
+		snapIDs, err := c.GetStorageGroupSnapshotSnapIDs(ctx, symID, storageGroupID, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range snapIDs.SnapIDs {
+			snap, err := c.GetStorageGroupSnapshotSnap(ctx, symID, storageGroupID, name, strconv.FormatInt(id, 10))
+			if err != nil {
+				return nil, err
+			}
+			if sgSnapBad(snap) {
+				compliance.Failed++
+				continue
+			}
+			compliance.Good++
+			taken := time.UnixMilli(snap.TimestampUtc)
+			if snap.TimestampUtc > 0 && taken.After(compliance.LastSuccess) {
+				compliance.LastSuccess = taken
+			}
+		}
+	}
+	if missing := compliance.Expected - compliance.Good - compliance.Failed; missing > 0 {
+		compliance.Missing = missing
+	}
+	compliance.State = snapshotPolicyState(policy, compliance.Good)
+	return compliance, nil
+}
+
+// GetSnapshotPolicyComplianceSummary returns the compliance of every storage group associated with every
+// snapshot policy on the array. Failures to evaluate individual policies or storage groups are recorded
+// in the summary rather than aborting it.
+func (c *Client) GetSnapshotPolicyComplianceSummary(ctx context.Context, symID string) (*SnapshotPolicyComplianceSummary, error) {
+	defer c.TimeSpent("GetSnapshotPolicyComplianceSummary", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	policies, err := c.GetSnapshotPolicyList(ctx, symID)
+	if err != nil {
+		return nil, err
+	}
+	summary := &SnapshotPolicyComplianceSummary{
+		SymmetrixID: symID,
+		Compliance:  make([]SnapshotPolicyCompliance, 0),
+		Errors:      make(map[string]string),
+	}
+	for _, policyID := range policies.SnapshotPolicyIDs {
+		summary.Policies++
+		policy, err := c.GetSnapshotPolicy(ctx, symID, policyID)
+		if err != nil {
+			summary.Errors[policyID] = err.Error()
+			continue
+		}
+		sgList, err := c.GetSnapshotPolicyStorageGroupList(ctx, symID, policyID)
+		if err != nil {
+			summary.Errors[policyID] = err.Error()
+			continue
+		}
+		for _, storageGroupID := range sgList.StorageGroupIDs {
+			compliance, err := c.getSnapshotPolicyCompliance(ctx, symID, policy, storageGroupID)
+			if err != nil {
+				log.Errorf("snapshot policy %s compliance for StorageGroup %s failed: %s", policyID, storageGroupID, err.Error())
+				summary.Errors[policyID+"/"+storageGroupID] = err.Error()
+				continue
+			}
+			switch compliance.State {
+			case SnapshotPolicyComplianceCritical:
+				summary.Critical++
+			case SnapshotPolicyComplianceWarning:
+				summary.Warning++
+			default:
+				summary.Normal++
+			}
+			summary.Compliance = append(summary.Compliance, *compliance)
+		}
+	}
+	return summary, nil
+}
@@ -0,0 +1,127 @@
+/*
+ *
+ * Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pmax
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotPolicyState(t *testing.T) {
+	policy := &types.SnapshotPolicy{ComplianceCountWarning: 10, ComplianceCountCritical: 5}
+	assert.Equal(t, SnapshotPolicyComplianceNormal, snapshotPolicyState(policy, 11))
+	assert.Equal(t, SnapshotPolicyComplianceWarning, snapshotPolicyState(policy, 10))
+	assert.Equal(t, SnapshotPolicyComplianceCritical, snapshotPolicyState(policy, 5))
+	assert.Equal(t, SnapshotPolicyComplianceNormal, snapshotPolicyState(&types.SnapshotPolicy{ComplianceCountWarning: -1}, 0))
+}
+
+// newSnapshotPolicyComplianceServer serves two policies: "daily" on sg_1 with three good snapshots and one
+// failed, and "hourly" on sg_2 whose storage group snapshot policy lookup fails.
+func newSnapshotPolicyComplianceServer(t *testing.T, last time.Time) *httptest.Server {
+	snaps := map[string]*types.StorageGroupSnap{
+		"1": {SnapID: 1, TimestampUtc: last.UnixMilli()},
+		"2": {SnapID: 2, TimestampUtc: last.Add(-24 * time.Hour).UnixMilli()},
+		"3": {SnapID: 3, TimestampUtc: last.Add(-48 * time.Hour).UnixMilli()},
+		"4": {SnapID: 4, TimestampUtc: last.Add(time.Hour).UnixMilli(), State: []string{"Failed"}},
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := r.URL.Path
+		last := path[strings.LastIndex(path, "/")+1:]
+		var out interface{}
+		switch {
+		case strings.HasSuffix(path, SnapshotPolicy):
+			out = &types.SnapshotPolicyList{SnapshotPolicyIDs: []string{"daily", "hourly"}}
+		case strings.HasSuffix(path, SnapshotPolicy+"/daily"):
+			out = &types.SnapshotPolicy{SnapshotPolicyName: "daily", SnapshotCount: 7, ComplianceCountWarning: 5, ComplianceCountCritical: 2}
+		case strings.HasSuffix(path, SnapshotPolicy+"/hourly"):
+			out = &types.SnapshotPolicy{SnapshotPolicyName: "hourly", SnapshotCount: 24}
+		case strings.HasSuffix(path, "daily"+XStorageGroup):
+			out = &types.SnapshotPolicyStorageGroupList{StorageGroupIDs: []string{"sg_1"}}
+		case strings.HasSuffix(path, "hourly"+XStorageGroup):
+			out = &types.SnapshotPolicyStorageGroupList{StorageGroupIDs: []string{"sg_2"}}
+		case strings.Contains(path, SnapshotPolicy) && last == "sg_2":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"not found"}`))
+			return
+		case strings.Contains(path, SnapshotPolicy):
+			out = &types.StorageGroupSnapshotPolicy{Compliance: "YELLOW"}
+		case strings.HasSuffix(path, XSnapshot):
+			assert.Equal(t, "true", r.URL.Query().Get("exclude_manual_snap"))
+			out = &types.StorageGroupSnapshot{SlSnapshotName: []string{"daily", "other"}}
+		case strings.HasSuffix(path, SnapID):
+			assert.Contains(t, path, "/daily/")
+			out = &types.SnapID{SnapIDs: []int64{1, 2, 3, 4}}
+		default:
+			out = snaps[last]
+		}
+		_ = json.NewEncoder(w).Encode(out)
+	}))
+}
+
+func TestGetSnapshotPolicyCompliance(t *testing.T) {
+	symID := "000000000001"
+	last := time.Date(2025, 3, 4, 5, 0, 0, 0, time.UTC)
+	server := newSnapshotPolicyComplianceServer(t, last)
+	defer server.Close()
+	c, err := NewClientWithArgs(server.URL, "", true, true, "")
+	assert.NoError(t, err)
+	c.SetAllowedArrays([]string{symID})
+
+	compliance, err := c.GetSnapshotPolicyCompliance(context.Background(), symID, "daily", "sg_1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), compliance.Expected)
+	assert.Equal(t, int64(3), compliance.Good)
+	assert.Equal(t, int64(1), compliance.Failed)
+	assert.Equal(t, int64(3), compliance.Missing)
+	assert.True(t, last.Equal(compliance.LastSuccess))
+	assert.Equal(t, SnapshotPolicyComplianceWarning, compliance.State)
+	assert.Equal(t, "YELLOW", compliance.ArrayCompliance)
+
+	_, err = c.GetSnapshotPolicyCompliance(context.Background(), symID, "hourly", "sg_2")
+	assert.Error(t, err)
+
+	_, err = c.GetSnapshotPolicyCompliance(context.Background(), "000000000002", "daily", "sg_1")
+	assert.Error(t, err)
+}
+
+func TestGetSnapshotPolicyComplianceSummary(t *testing.T) {
+	symID := "000000000001"
+	server := newSnapshotPolicyComplianceServer(t, time.Now())
+	defer server.Close()
+	c, err := NewClientWithArgs(server.URL, "", true, true, "")
+	assert.NoError(t, err)
+	c.SetAllowedArrays([]string{symID})
+
+	summary, err := c.GetSnapshotPolicyComplianceSummary(context.Background(), symID)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, summary.Policies)
+	assert.Equal(t, 1, summary.Warning)
+	assert.Equal(t, 0, summary.Normal+summary.Critical)
+	assert.Len(t, summary.Compliance, 1)
+	assert.Contains(t, summary.Errors, "hourly/sg_2")
+}
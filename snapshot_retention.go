@@ -0,0 +1,346 @@
+/*
+ Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pmax
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+	log "github.com/sirupsen/logrus"
+)
+
+// Reasons recorded against each snapshot evaluated by a retention policy.
+const (
+	RetentionReasonLinked           = "linked"
+	RetentionReasonSecured          = "secured"
+	RetentionReasonKeepLast         = "keep-last"
+	RetentionReasonInterval         = "interval"
+	RetentionReasonUnknownTimestamp = "unknown-timestamp"
+	RetentionReasonExpired          = "expired-by-policy"
+)
+
+// snapTimestampLayouts are the formats Unisphere uses for snapshot timestamps.
+var snapTimestampLayouts = []string{
+	"Mon Jan 02 15:04:05 2006",
+	time.ANSIC,
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+}
+
+// RetentionRule keeps the newest snapshot in each Every sized time bucket for snapshots
+// no older than For. For example {Every: time.Hour, For: 24 * time.Hour} keeps hourly snapshots for a day.
+type RetentionRule struct {
+	Every time.Duration
+	For   time.Duration
+}
+
+// SnapshotRetentionPolicy describes which snapshots are kept. A snapshot is kept if any of the
+// policy's conditions hold; all other snapshots matched by the policy are deleted.
+type SnapshotRetentionPolicy struct {
+	// KeepLast always keeps the newest KeepLast snapshots.
+	KeepLast int
+	// Rules keep snapshots at a reduced frequency as they age.
+	Rules []RetentionRule
+	// NeverDeleteSecured keeps secured snapshots even when no other condition holds.
+	NeverDeleteSecured bool
+	// Match restricts the policy to snapshot names for which it returns true. A nil Match selects every snapshot.
+	Match func(snapshotName string) bool
+}
+
+// Validate checks that the policy keeps at least something and that its rules are well formed.
+func (p SnapshotRetentionPolicy) Validate() error {
+	if p.KeepLast < 0 {
+		return fmt.Errorf("retention policy KeepLast must not be negative, got %d", p.KeepLast)
+	}
+	if p.KeepLast == 0 && len(p.Rules) == 0 {
+		return fmt.Errorf("retention policy must set KeepLast or at least one rule")
+	}
+	for _, rule := range p.Rules {
+		if rule.Every <= 0 || rule.For <= 0 {
+			return fmt.Errorf("retention rule every %s for %s must have positive durations", rule.Every, rule.For)
+		}
+	}
+	return nil
+}
+
+// SnapshotRetentionItem is one snapshot generation considered by a retention policy and the decision taken on it.
+type SnapshotRetentionItem struct {
+	SnapshotName string
+	Generation   int64
+	SnapID       int64
+	Timestamp    time.Time
+	Secured      bool
+	Linked       bool
+	Delete       bool
+	Reason       string
+	Deleted      bool
+	Error        string
+}
+
+// SnapshotRetentionReport is the result of applying a retention policy to a volume or storage group.
+type SnapshotRetentionReport struct {
+	SymmetrixID    string
+	VolumeID       string
+	StorageGroupID string
+	DryRun         bool
+	EvaluatedAt    time.Time
+	Items          []SnapshotRetentionItem
+	Kept           int
+	Deleted        int
+	Failed         int
+}
+
+// Candidates returns the items the policy selected for deletion.
+func (r *SnapshotRetentionReport) Candidates() []SnapshotRetentionItem {
+	candidates := make([]SnapshotRetentionItem, 0)
+	for _, item := range r.Items {
+		if item.Delete {
+			candidates = append(candidates, item)
+		}
+	}
+	return candidates
+}
+
+// EvaluateSnapshotRetention decides which of items to keep and which to delete under policy at time now.
+// Items not matched by the policy are dropped. The result is ordered newest first.
+func EvaluateSnapshotRetention(policy SnapshotRetentionPolicy, items []SnapshotRetentionItem, now time.Time) ([]SnapshotRetentionItem, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	evaluated := make([]SnapshotRetentionItem, 0, len(items))
+	for _, item := range items {
+		if policy.Match == nil || policy.Match(item.SnapshotName) {
+			evaluated = append(evaluated, item)
+		}
+	}
+	// Unknown timestamps sort last; they are always kept, so their position does not matter.
+	sort.SliceStable(evaluated, func(i, j int) bool {
+		return evaluated[i].Timestamp.After(evaluated[j].Timestamp)
+	})
+	buckets := make([]map[int64]bool, len(policy.Rules))
+	for i := range buckets {
+		buckets[i] = map[int64]bool{}
+	}
+	for i := range evaluated {
+		item := &evaluated[i]
+		item.Delete = false
+		switch {
+		case item.Linked:
+			item.Reason = RetentionReasonLinked
+		case item.Secured && policy.NeverDeleteSecured:
+			item.Reason = RetentionReasonSecured
+		case i < policy.KeepLast:
+			item.Reason = RetentionReasonKeepLast
+		case item.Timestamp.IsZero():
+			item.Reason = RetentionReasonUnknownTimestamp
+		default:
+			item.Delete = true
+			item.Reason = RetentionReasonExpired
+		}
+		if item.Timestamp.IsZero() {
+			continue
+		}
+		// Every snapshot claims its bucket, even if kept for another reason,
+		// so that older snapshots in the same bucket are not kept as well.
+		age := now.Sub(item.Timestamp)
+		for r, rule := range policy.Rules {
+			if age > rule.For {
+				continue
+			}
+			bucket := item.Timestamp.UnixNano() / int64(rule.Every)
+			if buckets[r][bucket] {
+				continue
+			}
+			buckets[r][bucket] = true
+			if item.Delete {
+				item.Delete = false
+				item.Reason = fmt.Sprintf("%s-%s", RetentionReasonInterval, rule.Every)
+			}
+		}
+	}
+	return evaluated, nil
+}
+
+// parseSnapTimestamp parses a Unisphere snapshot timestamp, returning the zero time if it is not recognised.
+func parseSnapTimestamp(timestamp string) time.Time {
+	timestamp = strings.TrimSpace(timestamp)
+	for _, layout := range snapTimestampLayouts {
+		if t, err := time.Parse(layout, timestamp); err == nil {
+			return t
+		}
+	}
+	if ms, err := strconv.ParseInt(timestamp, 10, 64); err == nil && ms > 0 {
+		return time.UnixMilli(ms)
+	}
+	return time.Time{}
+}
+
+// PruneVolumeSnapshots applies a retention policy to the snapshots of a volume.
+// In dry run mode the report lists the candidates without deleting anything.
+func (c *Client) PruneVolumeSnapshots(ctx context.Context, symID, volumeID string, policy SnapshotRetentionPolicy, dryRun bool) (*SnapshotRetentionReport, error) {
+	defer c.TimeSpent("PruneVolumeSnapshots", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	snapInfo, err := c.GetVolumeSnapInfo(ctx, symID, volumeID)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0)
+	for _, src := range snapInfo.VolumeSnapshotSource {
+		if !stringInSlice(src.SnapshotName, names) {
+			names = append(names, src.SnapshotName)
+		}
+	}
+	items := make([]SnapshotRetentionItem, 0)
+	for _, name := range names {
+		if policy.Match != nil && !policy.Match(name) {
+			continue
+		}
+		generations, err := c.GetSnapshotGenerations(ctx, symID, volumeID, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, src := range generations.VolumeSnapshotSource {
+			items = append(items, SnapshotRetentionItem{
+				SnapshotName: name,
+				Generation:   src.Generation,
+				SnapID:       src.SnapID,
+				Timestamp:    parseSnapTimestamp(src.TimeStamp),
+				Secured:      src.Secured,
+				Linked:       len(src.LinkedVolumes) > 0,
+			})
+		}
+	}
+	report := &SnapshotRetentionReport{SymmetrixID: symID, VolumeID: volumeID, DryRun: dryRun}
+	err = c.applySnapshotRetention(policy, items, report, func(item SnapshotRetentionItem) error {
+		return c.DeleteSnapshotS(ctx, symID, item.SnapshotName, []types.VolumeList{{Name: volumeID}}, item.Generation)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// PruneStorageGroupSnapshots applies a retention policy to the manual snapshots of a storage group.
+// Snapshot policy (service level) snapshots are not considered. In dry run mode the report lists
+// the candidates without deleting anything.
+func (c *Client) PruneStorageGroupSnapshots(ctx context.Context, symID, storageGroupID string, policy SnapshotRetentionPolicy, dryRun bool) (*SnapshotRetentionReport, error) {
+	defer c.TimeSpent("PruneStorageGroupSnapshots", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	sgSnapshots, err := c.GetStorageGroupSnapshots(ctx, symID, storageGroupID, false, true)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]SnapshotRetentionItem, 0)
+	for _, name := range sgSnapshots.Name {
+		if name == "" || (policy.Match != nil && !policy.Match(name)) {
+			continue
+		}
+		snapIDs, err := c.GetStorageGroupSnapshotSnapIDs(ctx, symID, storageGroupID, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range snapIDs.SnapIDs {
+			snap, err := c.GetStorageGroupSnapshotSnap(ctx, symID, storageGroupID, name, strconv.FormatInt(id, 10))
+			if err != nil {
+				return nil, err
+			}
+			item := SnapshotRetentionItem{
+				SnapshotName: name,
+				Generation:   snap.Generation,
+				SnapID:       snap.SnapID,
+				Secured:      sgSnapSecured(snap),
+				Linked:       snap.Linked || len(snap.LinkedStorageGroupNames) > 0,
+			}
+			if snap.TimestampUtc > 0 {
+				item.Timestamp = time.UnixMilli(snap.TimestampUtc)
+			} else {
+				item.Timestamp = parseSnapTimestamp(snap.Timestamp)
+			}
+			items = append(items, item)
+		}
+	}
+	report := &SnapshotRetentionReport{SymmetrixID: symID, StorageGroupID: storageGroupID, DryRun: dryRun}
+	err = c.applySnapshotRetention(policy, items, report, func(item SnapshotRetentionItem) error {
+		return c.DeleteStorageGroupSnapshot(ctx, symID, storageGroupID, item.SnapshotName, strconv.FormatInt(item.SnapID, 10))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// applySnapshotRetention evaluates the policy into report and, unless it is a dry run, deletes the candidates.
+// Deletes run oldest generation first so that the generation numbers of the remaining candidates do not shift.
+func (c *Client) applySnapshotRetention(policy SnapshotRetentionPolicy, items []SnapshotRetentionItem, report *SnapshotRetentionReport,
+	deleteFn func(item SnapshotRetentionItem) error,
+) error {
+	report.EvaluatedAt = time.Now()
+	evaluated, err := EvaluateSnapshotRetention(policy, items, report.EvaluatedAt)
+	if err != nil {
+		return err
+	}
+	report.Items = evaluated
+	order := make([]int, 0)
+	for i, item := range report.Items {
+		if item.Delete {
+			order = append(order, i)
+		} else {
+			report.Kept++
+		}
+	}
+	if report.DryRun {
+		return nil
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return report.Items[order[i]].Generation > report.Items[order[j]].Generation
+	})
+	for _, i := range order {
+		item := &report.Items[i]
+		if err := deleteFn(*item); err != nil {
+			log.Errorf("retention delete of snapshot %s generation %d failed: %s", item.SnapshotName, item.Generation, err.Error())
+			item.Error = err.Error()
+			report.Failed++
+			continue
+		}
+		item.Deleted = true
+		report.Deleted++
+	}
+	return nil
+}
+
+func sgSnapSecured(snap *types.StorageGroupSnap) bool {
+	for _, state := range snap.State {
+		if strings.Contains(strings.ToLower(state), "secure") {
+			return true
+		}
+	}
+	expiry := strings.TrimSpace(snap.SecureExpiryDate)
+	return expiry != "" && !strings.EqualFold(expiry, "N/A")
+}
@@ -0,0 +1,259 @@
+/*
+ *
+ * Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pmax
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluateSnapshotRetention(t *testing.T) {
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	hoursAgo := func(h int) time.Time { return now.Add(-time.Duration(h) * time.Hour) }
+	tests := []struct {
+		name       string
+		policy     SnapshotRetentionPolicy
+		items      []SnapshotRetentionItem
+		wantDelete []string
+		wantReason map[string]string
+		wantErr    string
+	}{
+		{
+			name:    "empty policy rejected",
+			policy:  SnapshotRetentionPolicy{},
+			wantErr: "must set KeepLast or at least one rule",
+		},
+		{
+			name:    "bad rule rejected",
+			policy:  SnapshotRetentionPolicy{Rules: []RetentionRule{{Every: 0, For: time.Hour}}},
+			wantErr: "must have positive durations",
+		},
+		{
+			name:   "keep last",
+			policy: SnapshotRetentionPolicy{KeepLast: 2},
+			items: []SnapshotRetentionItem{
+				{SnapshotName: "a", Timestamp: hoursAgo(3)},
+				{SnapshotName: "b", Timestamp: hoursAgo(1)},
+				{SnapshotName: "c", Timestamp: hoursAgo(2)},
+				{SnapshotName: "d", Timestamp: hoursAgo(4)},
+			},
+			wantDelete: []string{"a", "d"},
+			wantReason: map[string]string{"b": RetentionReasonKeepLast, "a": RetentionReasonExpired},
+		},
+		{
+			name: "hourly then daily",
+			policy: SnapshotRetentionPolicy{Rules: []RetentionRule{
+				{Every: time.Hour, For: 6 * time.Hour},
+				{Every: 24 * time.Hour, For: 3 * 24 * time.Hour},
+			}},
+			items: []SnapshotRetentionItem{
+				{SnapshotName: "h1", Timestamp: now.Add(-30 * time.Minute)},
+				{SnapshotName: "h1-dup", Timestamp: now.Add(-45 * time.Minute)},
+				{SnapshotName: "h2", Timestamp: now.Add(-90 * time.Minute)},
+				{SnapshotName: "d1", Timestamp: hoursAgo(30)},
+				{SnapshotName: "d1-dup", Timestamp: hoursAgo(31)},
+				{SnapshotName: "old", Timestamp: hoursAgo(24 * 10)},
+			},
+			wantDelete: []string{"h1-dup", "d1-dup", "old"},
+			wantReason: map[string]string{"h1": "interval-1h0m0s", "d1": "interval-24h0m0s"},
+		},
+		{
+			name:   "linked, secured and unknown timestamps are kept",
+			policy: SnapshotRetentionPolicy{KeepLast: 1, NeverDeleteSecured: true},
+			items: []SnapshotRetentionItem{
+				{SnapshotName: "new", Timestamp: hoursAgo(1)},
+				{SnapshotName: "linked", Timestamp: hoursAgo(2), Linked: true},
+				{SnapshotName: "secured", Timestamp: hoursAgo(3), Secured: true},
+				{SnapshotName: "unknown"},
+				{SnapshotName: "old", Timestamp: hoursAgo(4)},
+			},
+			wantDelete: []string{"old"},
+			wantReason: map[string]string{
+				"linked":  RetentionReasonLinked,
+				"secured": RetentionReasonSecured,
+				"unknown": RetentionReasonUnknownTimestamp,
+			},
+		},
+		{
+			name: "match limits the policy",
+			policy: SnapshotRetentionPolicy{KeepLast: 1, Match: func(name string) bool {
+				return strings.HasPrefix(name, "daily")
+			}},
+			items: []SnapshotRetentionItem{
+				{SnapshotName: "daily-2", Timestamp: hoursAgo(1)},
+				{SnapshotName: "daily-1", Timestamp: hoursAgo(25)},
+				{SnapshotName: "manual", Timestamp: hoursAgo(50)},
+			},
+			wantDelete: []string{"daily-1"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := EvaluateSnapshotRetention(tc.policy, tc.items, now)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			deleted := make([]string, 0)
+			reasons := map[string]string{}
+			for _, item := range result {
+				if item.Delete {
+					deleted = append(deleted, item.SnapshotName)
+				}
+				reasons[item.SnapshotName] = item.Reason
+			}
+			assert.ElementsMatch(t, tc.wantDelete, deleted)
+			for name, reason := range tc.wantReason {
+				assert.Equal(t, reason, reasons[name], name)
+			}
+		})
+	}
+}
+
+func TestParseSnapTimestamp(t *testing.T) {
+	want := time.Date(2022, 11, 14, 9, 58, 1, 0, time.UTC)
+	assert.Equal(t, want, parseSnapTimestamp("Mon Nov 14 09:58:01 2022"))
+	assert.Equal(t, want, parseSnapTimestamp("2022-11-14T09:58:01Z"))
+	assert.True(t, want.Equal(parseSnapTimestamp("1668419881000")))
+	assert.True(t, parseSnapTimestamp("bogus").IsZero())
+}
+
+func TestPruneVolumeSnapshots(t *testing.T) {
+	now := time.Now().UTC()
+	stamp := func(h int) string {
+		return now.Add(-time.Duration(h) * time.Hour).Format("Mon Jan 02 15:04:05 2006")
+	}
+	sources := []types.VolumeSnapshotSource{
+		{SnapshotName: "nightly", Generation: 0, TimeStamp: stamp(1)},
+		{SnapshotName: "nightly", Generation: 1, TimeStamp: stamp(25)},
+		{SnapshotName: "nightly", Generation: 2, TimeStamp: stamp(49), LinkedVolumes: []types.LinkedVolumes{{TargetDevice: "00002"}}},
+		{SnapshotName: "nightly", Generation: 3, TimeStamp: stamp(73)},
+		{SnapshotName: "nightly", Generation: 4, TimeStamp: stamp(97), Secured: true},
+	}
+	tests := []struct {
+		name        string
+		dryRun      bool
+		failDelete  bool
+		wantDeletes []int64
+		wantDeleted int
+		wantFailed  int
+	}{
+		{name: "dry run", dryRun: true},
+		{name: "apply deletes oldest generation first", wantDeletes: []int64{3, 1}, wantDeleted: 2},
+		{name: "apply reports failures", failDelete: true, wantDeletes: []int64{3, 1}, wantFailed: 2},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var mu sync.Mutex
+			deletes := make([]int64, 0)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case r.Method == http.MethodDelete:
+					payload := &types.DeleteVolumeSnapshot{}
+					_ = json.NewDecoder(r.Body).Decode(payload)
+					mu.Lock()
+					deletes = append(deletes, payload.Generation)
+					mu.Unlock()
+					if tc.failDelete {
+						w.WriteHeader(http.StatusBadRequest)
+						_, _ = w.Write([]byte(`{"message":"induced error"}`))
+					}
+				case strings.HasSuffix(r.URL.Path, XGenereation):
+					_ = json.NewEncoder(w).Encode(&types.VolumeSnapshotGenerations{SnapshotName: "nightly", VolumeSnapshotSource: sources})
+				default:
+					_ = json.NewEncoder(w).Encode(&types.SnapshotVolumeGeneration{DeviceName: "00001", VolumeSnapshotSource: sources[:1]})
+				}
+			}))
+			defer server.Close()
+			c, err := NewClientWithArgs(server.URL, "", true, true, "")
+			assert.NoError(t, err)
+			c.SetAllowedArrays([]string{"000000000001"})
+
+			policy := SnapshotRetentionPolicy{KeepLast: 1, NeverDeleteSecured: true}
+			report, err := c.PruneVolumeSnapshots(context.Background(), "000000000001", "00001", policy, tc.dryRun)
+			assert.NoError(t, err)
+			assert.Len(t, report.Items, 5)
+			assert.Len(t, report.Candidates(), 2)
+			assert.Equal(t, 3, report.Kept)
+			assert.Equal(t, tc.wantDeleted, report.Deleted)
+			assert.Equal(t, tc.wantFailed, report.Failed)
+			assert.Equal(t, tc.wantDeletes, nilIfEmpty(deletes))
+		})
+	}
+}
+
+func TestPruneStorageGroupSnapshots(t *testing.T) {
+	now := time.Now()
+	snaps := map[string]*types.StorageGroupSnap{
+		"1": {Name: "backup", SnapID: 1, Generation: 0, TimestampUtc: now.Add(-time.Hour).UnixMilli()},
+		"2": {Name: "backup", SnapID: 2, Generation: 1, TimestampUtc: now.Add(-2 * time.Hour).UnixMilli(), SecureExpiryDate: "N/A"},
+		"3": {Name: "backup", SnapID: 3, Generation: 2, TimestampUtc: now.Add(-3 * time.Hour).UnixMilli(), State: []string{"Secured"}},
+	}
+	var mu sync.Mutex
+	deleted := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := r.URL.Path
+		switch {
+		case strings.HasSuffix(path, XSnapshot):
+			assert.Equal(t, "true", r.URL.Query().Get("exclude_sl_snaps"))
+			_ = json.NewEncoder(w).Encode(&types.StorageGroupSnapshot{Name: []string{"backup"}})
+		case strings.HasSuffix(path, SnapID):
+			_ = json.NewEncoder(w).Encode(&types.SnapID{SnapIDs: []int64{1, 2, 3}})
+		case r.Method == http.MethodDelete:
+			mu.Lock()
+			deleted = append(deleted, path[strings.LastIndex(path, "/")+1:])
+			mu.Unlock()
+		default:
+			_ = json.NewEncoder(w).Encode(snaps[path[strings.LastIndex(path, "/")+1:]])
+		}
+	}))
+	defer server.Close()
+	c, err := NewClientWithArgs(server.URL, "", true, true, "")
+	assert.NoError(t, err)
+	c.SetAllowedArrays([]string{"000000000001"})
+
+	policy := SnapshotRetentionPolicy{KeepLast: 1, NeverDeleteSecured: true}
+	report, err := c.PruneStorageGroupSnapshots(context.Background(), "000000000001", "sg_1", policy, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "sg_1", report.StorageGroupID)
+	assert.Equal(t, 1, report.Deleted)
+	assert.Equal(t, []string{"2"}, deleted)
+
+	_, err = c.PruneStorageGroupSnapshots(context.Background(), "000000000001", "sg_1", SnapshotRetentionPolicy{}, true)
+	assert.Error(t, err)
+}
+
+func nilIfEmpty(in []int64) []int64 {
+	if len(in) == 0 {
+		return nil
+	}
+	return in
+}
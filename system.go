@@ -193,19 +193,32 @@ func (c *Client) WaitOnJobCompletion(ctx context.Context, symID string, jobID st
 	if _, err := c.IsAllowedArray(symID); err != nil {
 		return nil, err
 	}
+	return c.waitOnJob(ctx, symID, jobID, nil)
+}
+
+// waitOnJob polls a Job until it reaches a terminal state, passing each state read to progress if it is not nil.
+// It stops early with the error of ctx once ctx is done.
+func (c *Client) waitOnJob(ctx context.Context, symID string, jobID string, progress func(*types.Job)) (*types.Job, error) {
 	for i := 0; i < MAXJobRetryCount; i++ {
 		job, err := c.GetJobByID(ctx, symID, jobID)
 		if err != nil {
 			return nil, err
 		}
 		log.Debug(c.JobToString(job))
+		if progress != nil {
+			progress(job)
+		}
 		switch job.Status {
 		case types.JobStatusSucceeded:
 			return job, nil
 		case types.JobStatusFailed:
 			return job, nil
 		}
-		time.Sleep(JobRetrySleepDuration)
+		select {
+		case <-ctx.Done():
+			return job, ctx.Err()
+		case <-time.After(JobRetrySleepDuration):
+		}
 	}
 	return nil, fmt.Errorf("Symmetrix %s Job %s timed out after %d retries", symID, jobID, MAXJobRetryCount)
 }
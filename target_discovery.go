@@ -0,0 +1,223 @@
+/*
+ Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pmax
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+	log "github.com/sirupsen/logrus"
+)
+
+// Target discovery protocols
+const (
+	DiscoveryProtocolISCSI   = "iSCSI"
+	DiscoveryProtocolNVMeTCP = "NVMeTCP"
+)
+
+// TargetDiscoveryOptions controls DiscoverTargets.
+type TargetDiscoveryOptions struct {
+	// Protocol is DiscoveryProtocolISCSI or DiscoveryProtocolNVMeTCP.
+	Protocol string
+	// PortGroupID, if set, restricts discovery to the ports of this port group.
+	PortGroupID string
+	// Concurrency bounds the number of requests in flight. DefaultRequestConcurrency is used if it is not positive.
+	Concurrency int
+}
+
+// DiscoveredTarget is an iSCSI or NVMe/TCP target port and its portal addresses.
+type DiscoveredTarget struct {
+	DirectorID string
+	PortID     string
+	// Identifier is the IQN of an iSCSI target or the NQN of an NVMe/TCP target.
+	Identifier string
+	PortalIPs  []string
+	PortStatus string
+}
+
+// TargetDiscoveryError records a director or port which could not be inspected.
+// PortID is empty when listing the ports of the director failed.
+type TargetDiscoveryError struct {
+	DirectorID string
+	PortID     string
+	Err        error
+}
+
+func (e *TargetDiscoveryError) Error() string {
+	if e.PortID == "" {
+		return fmt.Sprintf("director %s: %s", e.DirectorID, e.Err.Error())
+	}
+	return fmt.Sprintf("port %s:%s: %s", e.DirectorID, e.PortID, e.Err.Error())
+}
+
+func (e *TargetDiscoveryError) Unwrap() error {
+	return e.Err
+}
+
+// TargetDiscoveryResult holds the targets found by DiscoverTargets and the failures met along the way.
+type TargetDiscoveryResult struct {
+	Targets []DiscoveredTarget
+	Errors  []*TargetDiscoveryError
+}
+
+// discoveryQueries returns the port list queries which identify, respectively, the directors
+// carrying a protocol and the target ports on those directors.
+func discoveryQueries(protocol string) (string, string, error) {
+	switch protocol {
+	case DiscoveryProtocolISCSI:
+		return "type=Gige", "iscsi_target=true", nil
+	case DiscoveryProtocolNVMeTCP:
+		return "type=OSHostAndRDF", "nvmetcp_endpoint=true", nil
+	default:
+		return "", "", fmt.Errorf("unsupported target discovery protocol %q", protocol)
+	}
+}
+
+// portKeyString returns a normalised director:port key, ignoring case and leading zeros in the port.
+// A port ID which already carries its director ("FA-1D:4") is accepted.
+func portKeyString(directorID, portID string) string {
+	if i := strings.LastIndex(portID, ":"); i >= 0 {
+		portID = portID[i+1:]
+	}
+	port := strings.TrimLeft(portID, "0")
+	if port == "" {
+		port = "0"
+	}
+	return strings.ToUpper(directorID) + ":" + port
+}
+
+// DiscoverTargets finds the iSCSI or NVMe/TCP target ports of an array. Directors and then ports are
+// inspected concurrently, with at most opts.Concurrency requests in flight. Failures on individual directors
+// or ports do not stop discovery; they are returned in the result. An error is returned only if the
+// directors (or the port group) to inspect cannot be determined.
+func (c *Client) DiscoverTargets(ctx context.Context, symID string, opts TargetDiscoveryOptions) (*TargetDiscoveryResult, error) {
+	defer c.TimeSpent("DiscoverTargets", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	typeQuery, targetQuery, err := discoveryQueries(opts.Protocol)
+	if err != nil {
+		return nil, err
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultRequestConcurrency
+	}
+
+	var directorIDs []string
+	var portFilter map[string]bool
+	if opts.PortGroupID != "" {
+		portGroup, err := c.GetPortGroupByID(ctx, symID, opts.PortGroupID)
+		if err != nil {
+			return nil, err
+		}
+		portFilter = make(map[string]bool, len(portGroup.SymmetrixPortKey))
+		for _, key := range portGroup.SymmetrixPortKey {
+			portFilter[portKeyString(key.DirectorID, key.PortID)] = true
+			if !stringInSlice(key.DirectorID, directorIDs) {
+				directorIDs = append(directorIDs, key.DirectorID)
+			}
+		}
+	} else {
+		directors, err := c.GetDirectorIDList(ctx, symID)
+		if err != nil {
+			return nil, err
+		}
+		directorIDs = directors.DirectorIDs
+	}
+
+	result := &TargetDiscoveryResult{
+		Targets: make([]DiscoveredTarget, 0),
+		Errors:  make([]*TargetDiscoveryError, 0),
+	}
+	var mu sync.Mutex
+	addError := func(directorID, portID string, err error) {
+		log.Errorf("target discovery on %s failed for director %s port %s: %s", symID, directorID, portID, err.Error())
+		mu.Lock()
+		result.Errors = append(result.Errors, &TargetDiscoveryError{DirectorID: directorID, PortID: portID, Err: err})
+		mu.Unlock()
+	}
+
+	// Phase one lists the target ports of every director, phase two fetches each port.
+	// Keeping the phases separate means a worker never waits on another worker's slot.
+	targetPorts := make([]types.PortKey, 0)
+	if err := runBounded(ctx, concurrency, len(directorIDs), func(i int) {
+		directorID := directorIDs[i]
+		ports, err := c.GetPortList(ctx, symID, directorID, typeQuery)
+		if err != nil {
+			addError(directorID, "", err)
+			return
+		}
+		if len(ports.SymmetrixPortKey) == 0 {
+			return
+		}
+		virtualPorts, err := c.GetPortList(ctx, symID, directorID, targetQuery)
+		if err != nil {
+			addError(directorID, "", err)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, key := range virtualPorts.SymmetrixPortKey {
+			if key.DirectorID == "" {
+				key.DirectorID = directorID
+			}
+			if portFilter != nil && !portFilter[portKeyString(key.DirectorID, key.PortID)] {
+				continue
+			}
+			targetPorts = append(targetPorts, key)
+		}
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := runBounded(ctx, concurrency, len(targetPorts), func(i int) {
+		key := targetPorts[i]
+		port, err := c.GetPort(ctx, symID, key.DirectorID, key.PortID)
+		if err != nil {
+			addError(key.DirectorID, key.PortID, err)
+			return
+		}
+		if port.SymmetrixPort.Identifier == "" {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		result.Targets = append(result.Targets, DiscoveredTarget{
+			DirectorID: key.DirectorID,
+			PortID:     key.PortID,
+			Identifier: port.SymmetrixPort.Identifier,
+			PortalIPs:  port.SymmetrixPort.IPAddresses,
+			PortStatus: port.SymmetrixPort.PortStatus,
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(result.Targets, func(i, j int) bool {
+		return portKeyString(result.Targets[i].DirectorID, result.Targets[i].PortID) <
+			portKeyString(result.Targets[j].DirectorID, result.Targets[j].PortID)
+	})
+	sort.Slice(result.Errors, func(i, j int) bool {
+		return portKeyString(result.Errors[i].DirectorID, result.Errors[i].PortID) <
+			portKeyString(result.Errors[j].DirectorID, result.Errors[j].PortID)
+	})
+	return result, nil
+}
@@ -0,0 +1,182 @@
+/*
+ *
+ * Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pmax
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dell/gopowermax/v2/mock"
+	types "github.com/dell/gopowermax/v2/types/v100"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeTargetArray serves four directors: SE-1A with two iSCSI targets, SE-1B whose port list fails,
+// FA-1D with no GigE ports and SE-2A whose port 3 cannot be read.
+type fakeTargetArray struct {
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+}
+
+func (f *fakeTargetArray) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.inFlight++
+	if f.inFlight > f.peak {
+		f.peak = f.inFlight
+	}
+	f.mu.Unlock()
+	defer func() {
+		f.mu.Lock()
+		f.inFlight--
+		f.mu.Unlock()
+	}()
+	time.Sleep(5 * time.Millisecond)
+
+	w.Header().Set("Content-Type", "application/json")
+	fail := func() {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"message":"induced error"}`))
+	}
+	parts := strings.Split(strings.TrimSuffix(r.URL.Path, "/"), "/")
+	last := parts[len(parts)-1]
+	var out interface{}
+	switch {
+	case strings.Contains(r.URL.Path, "/portgroup/"):
+		out = &types.PortGroup{PortGroupID: last, SymmetrixPortKey: []types.PortKey{
+			{DirectorID: "SE-1A", PortID: "000"},
+			{DirectorID: "SE-1B", PortID: "0"},
+		}}
+	case last == "director":
+		out = &types.DirectorIDList{DirectorIDs: []string{"SE-2A", "SE-1A", "SE-1B", "FA-1D"}}
+	case last == "port":
+		director := parts[len(parts)-2]
+		switch {
+		case director == "SE-1B":
+			fail()
+			return
+		case director == "FA-1D" && r.URL.Query().Get("type") != "":
+			out = &types.PortList{}
+		case r.URL.Query().Get("type") != "":
+			out = &types.PortList{SymmetrixPortKey: []types.PortKey{{DirectorID: director, PortID: "0"}}}
+		default:
+			out = &types.PortList{SymmetrixPortKey: []types.PortKey{{DirectorID: director, PortID: "0"}, {DirectorID: director, PortID: "3"}}}
+		}
+	default:
+		director := parts[len(parts)-3]
+		if director == "SE-2A" && last == "3" {
+			fail()
+			return
+		}
+		out = &types.Port{SymmetrixPort: types.SymmetrixPortType{
+			Identifier:  "iqn.1992-04.com.emc:" + strings.ToLower(director) + "-" + last,
+			IPAddresses: []string{"10.0.0." + last},
+			PortStatus:  "ON",
+		}}
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func TestDiscoverTargets(t *testing.T) {
+	symID := "000000000001"
+	tests := []struct {
+		name        string
+		opts        TargetDiscoveryOptions
+		wantTargets []string
+		wantErrors  []string
+		wantErr     string
+	}{
+		{
+			name:        "all directors",
+			opts:        TargetDiscoveryOptions{Protocol: DiscoveryProtocolISCSI, Concurrency: 2},
+			wantTargets: []string{"SE-1A:0", "SE-1A:3", "SE-2A:0"},
+			wantErrors:  []string{"director SE-1B: induced error", "port SE-2A:3: induced error"},
+		},
+		{
+			name:        "port group filter",
+			opts:        TargetDiscoveryOptions{Protocol: DiscoveryProtocolNVMeTCP, PortGroupID: "pg_1"},
+			wantTargets: []string{"SE-1A:0"},
+			wantErrors:  []string{"director SE-1B: induced error"},
+		},
+		{
+			name:    "bad protocol",
+			opts:    TargetDiscoveryOptions{Protocol: "FC"},
+			wantErr: "unsupported target discovery protocol",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			array := &fakeTargetArray{}
+			server := httptest.NewServer(array)
+			defer server.Close()
+			c, err := NewClientWithArgs(server.URL, "", true, true, "")
+			assert.NoError(t, err)
+			c.SetAllowedArrays([]string{symID})
+
+			result, err := c.DiscoverTargets(context.Background(), symID, tc.opts)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			targets := make([]string, 0)
+			for _, target := range result.Targets {
+				targets = append(targets, target.DirectorID+":"+target.PortID)
+				assert.NotEmpty(t, target.Identifier)
+				assert.Equal(t, []string{"10.0.0." + target.PortID}, target.PortalIPs)
+			}
+			assert.Equal(t, tc.wantTargets, targets)
+			errs := make([]string, 0)
+			for _, e := range result.Errors {
+				errs = append(errs, e.Error())
+			}
+			assert.Equal(t, tc.wantErrors, errs)
+			if tc.opts.Concurrency > 0 {
+				assert.LessOrEqual(t, array.peak, tc.opts.Concurrency)
+			}
+		})
+	}
+}
+
+func TestDiscoverTargetsMock(t *testing.T) {
+	c := newMockReplicationClient(t)
+	result, err := c.DiscoverTargets(context.Background(), mock.DefaultSymmetrixID, TargetDiscoveryOptions{Protocol: DiscoveryProtocolISCSI})
+	assert.NoError(t, err)
+	assert.Empty(t, result.Errors)
+	targets, err := c.GetISCSITargets(context.Background(), mock.DefaultSymmetrixID)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, targets)
+	assert.Len(t, result.Targets, len(targets))
+
+	mock.InducedErrors.GetPortISCSITargetError = true
+	result, err = c.DiscoverTargets(context.Background(), mock.DefaultSymmetrixID, TargetDiscoveryOptions{Protocol: DiscoveryProtocolISCSI})
+	assert.NoError(t, err)
+	assert.Empty(t, result.Targets)
+	assert.NotEmpty(t, result.Errors)
+
+	mock.InducedErrors.GetDirectorError = true
+	_, err = c.DiscoverTargets(context.Background(), mock.DefaultSymmetrixID, TargetDiscoveryOptions{Protocol: DiscoveryProtocolISCSI})
+	assert.Error(t, err)
+}
@@ -176,3 +176,56 @@ type FileSystemResult struct {
 	PercentBusy float64 `json:"PercentBusy"`
 	Timestamp   int64   `json:"timestamp"`
 }
+
+// FEPortKeysParam is the request for the available front end port performance keys
+type FEPortKeysParam struct {
+	SymmetrixID string `json:"symmetrixId"`
+	DirectorID  string `json:"directorId"`
+}
+
+// FEPortKeysResult is the list of front end port info
+type FEPortKeysResult struct {
+	FEPortInfos []FEPortInfo `json:"fePortInfo"`
+}
+
+// FEPortInfo is the information of the front end port key
+type FEPortInfo struct {
+	PortID             string `json:"portId"`
+	FirstAvailableDate int64  `json:"firstAvailableDate"`
+	LastAvailableDate  int64  `json:"lastAvailableDate"`
+}
+
+// FEPortMetricsParam contains req param for front end port metrics
+type FEPortMetricsParam struct {
+	SymmetrixID string   `json:"symmetrixId"`
+	DirectorID  string   `json:"directorId"`
+	PortID      string   `json:"portId"`
+	StartDate   int64    `json:"startDate"`
+	EndDate     int64    `json:"endDate"`
+	DataFormat  string   `json:"dataFormat"`
+	Metrics     []string `json:"metrics"`
+}
+
+// FEPortMetricsIterator contains the result of query
+type FEPortMetricsIterator struct {
+	ResultList     FEPortMetricsResultList `json:"resultList"`
+	ID             string                  `json:"id"`
+	Count          int                     `json:"count"`
+	ExpirationTime int64                   `json:"expirationTime"`
+	MaxPageSize    int                     `json:"maxPageSize"`
+}
+
+// FEPortMetricsResultList contains the list of front end port metrics
+type FEPortMetricsResultList struct {
+	Result []FEPortMetric `json:"result"`
+	From   int            `json:"from"`
+	To     int            `json:"to"`
+}
+
+// FEPortMetric is the struct of front end port metric
+type FEPortMetric struct {
+	PercentBusy float64 `json:"PercentBusy"`
+	IOs         float64 `json:"IOs"`
+	MBs         float64 `json:"MBs"`
+	Timestamp   int64   `json:"timestamp"`
+}
@@ -100,6 +100,8 @@ type ModifySnapshotPolicyParam struct {
 	ComplianceCountCritical int64 `json:"compliance_count_critical,omitempty"`
 	// The number of the snapshots that will be maintained by the snapshot policy
 	SnapshotCount int32 `json:"snapshot_count,omitempty"`
+	// The number of days that snapshots will be retained in the cloud for. Only applies to cloud policies.
+	CloudRetentionDays int32 `json:"cloud_retention_days,omitempty"`
 }
 
 // AssociateStorageGroupParam defines storage group ids that you want to add to the Snapshot Policy
@@ -131,3 +133,36 @@ type UpdateSnapshotPolicyParam struct {
 type SnapshotPolicyList struct {
 	SnapshotPolicyIDs []string `json:"name"`
 }
+
+// SnapshotPolicyStorageGroupList contains the names of the storage groups associated with a snapshot policy
+type SnapshotPolicyStorageGroupList struct {
+	StorageGroupIDs []string `json:"name"`
+}
+
+// CloudProviderList contains the names of the cloud providers configured on a System
+type CloudProviderList struct {
+	CloudProviderIDs []string `json:"name"`
+}
+
+// CloudSnapshot holds the details of a storage group snapshot resident in a cloud provider
+type CloudSnapshot struct {
+	// The ID of the snapshot in the cloud provider
+	SnapshotID string `json:"snapshot_id"`
+	// The name of the snapshot
+	SnapshotName string `json:"snapshot_name"`
+	// The name of the cloud provider holding the snapshot
+	CloudProviderName string `json:"cloud_provider_name"`
+	// The name of the snapshot policy that created the snapshot, if any
+	SnapshotPolicyName string `json:"snapshot_policy_name,omitempty"`
+	// The time the snapshot was taken, in milliseconds since the epoch
+	TimestampUtc int64 `json:"timestamp_utc"`
+	// The time the snapshot expires from the cloud, in milliseconds since the epoch
+	ExpiryTimestampUtc int64 `json:"expiry_timestamp_utc,omitempty"`
+	// The state of the snapshot
+	State string `json:"state,omitempty"`
+}
+
+// CloudSnapshotList contains the cloud snapshots of a storage group
+type CloudSnapshotList struct {
+	CloudSnapshots []CloudSnapshot `json:"cloud_snapshot"`
+}
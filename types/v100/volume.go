@@ -92,6 +92,15 @@ type VolumeEnhanced struct {
 	VolumeHostPaths         []VolumeHostPath `json:"volume_host_paths,omitempty"`
 	NumberOfMaskingViews    int              `json:"num_of_masking_views,omitempty"`
 	SRP                     Srp              `json:"srp,omitempty"`
+	WWN                     string           `json:"wwn,omitempty"`
+	EffectiveWWN            string           `json:"effective_wwn,omitempty"`
+	EncapsulatedWWN         string           `json:"encapsulated_wwn,omitempty"`
+	NGUID                   string           `json:"nguid,omitempty"`
+	AllocatedPercent        float64          `json:"allocated_percent,omitempty"`
+	Emulation               string           `json:"emulation,omitempty"`
+	SnapVXSource            bool             `json:"snapvx_source,omitempty"`
+	SnapVXTarget            bool             `json:"snapvx_target,omitempty"`
+	NumOfFrontEndPaths      int              `json:"num_of_front_end_paths,omitempty"`
 }
 
 type VolumeHostPath struct {
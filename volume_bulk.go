@@ -0,0 +1,410 @@
+/*
+ Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pmax
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+	log "github.com/sirupsen/logrus"
+)
+
+// BulkVolumeSpec describes one volume to be created by CreateVolumesBulk.
+type BulkVolumeSpec struct {
+	// RequestID identifies the volume in the results. It defaults to Identifier, or to the
+	// position of the spec if Identifier is empty, and must be unique within a request.
+	RequestID  string
+	Identifier string
+	Size       float64
+	// CapacityUnit is one of the types.CapacityUnit constants; GB is used if it is empty.
+	CapacityUnit string
+	// StorageGroupID is the storage group the volume is added to, if any.
+	StorageGroupID   string
+	EnableMobilityID bool
+}
+
+// BulkVolumeResult is the outcome of one BulkVolumeSpec.
+type BulkVolumeResult struct {
+	Spec     BulkVolumeSpec
+	VolumeID string
+	// StorageGroups are the storage groups the volume was reported in.
+	StorageGroups []string
+	// Err is set if the volume was not created or not added to its storage group. A volume that was
+	// created but not added to its storage group has a VolumeID and a *BulkVolumeStorageGroupError.
+	Err error
+}
+
+// BulkVolumeStorageGroupError is the error of a volume that was created but not added to its storage group.
+type BulkVolumeStorageGroupError struct {
+	VolumeID       string
+	StorageGroupID string
+	// Err is the error of the last attempt to add the volume, if it was retried.
+	Err error
+}
+
+func (e *BulkVolumeStorageGroupError) Error() string {
+	msg := fmt.Sprintf("volume %s was not added to storage group %s", e.VolumeID, e.StorageGroupID)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// BulkVolumeResults maps each request ID of a bulk create to its result.
+type BulkVolumeResults map[string]*BulkVolumeResult
+
+// sortedResults returns the results matching keep ordered by request ID.
+func (r BulkVolumeResults) sortedResults(keep func(*BulkVolumeResult) bool) []*BulkVolumeResult {
+	results := make([]*BulkVolumeResult, 0)
+	for _, result := range r {
+		if keep(result) {
+			results = append(results, result)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Spec.RequestID < results[j].Spec.RequestID })
+	return results
+}
+
+// Failed returns the specs of the volumes which were not created, ordered by request ID, so that they can be
+// created again. Volumes which were created with an error are not included, see NotInStorageGroup.
+func (r BulkVolumeResults) Failed() []BulkVolumeSpec {
+	failed := make([]BulkVolumeSpec, 0)
+	for _, result := range r.sortedResults(func(result *BulkVolumeResult) bool {
+		return result.Err != nil && result.VolumeID == ""
+	}) {
+		failed = append(failed, result.Spec)
+	}
+	return failed
+}
+
+// NotInStorageGroup returns the results of the volumes which were created but not added to their storage
+// group, ordered by request ID.
+func (r BulkVolumeResults) NotInStorageGroup() []*BulkVolumeResult {
+	return r.sortedResults(func(result *BulkVolumeResult) bool {
+		var sgErr *BulkVolumeStorageGroupError
+		return result.VolumeID != "" && errors.As(result.Err, &sgErr)
+	})
+}
+
+// Err returns an error describing the failed volumes, or nil if every volume was created.
+func (r BulkVolumeResults) Err() error {
+	failed := r.sortedResults(func(result *BulkVolumeResult) bool { return result.Err != nil })
+	if len(failed) == 0 {
+		return nil
+	}
+	messages := make([]string, 0, len(failed))
+	for _, result := range failed {
+		messages = append(messages, result.Spec.RequestID+": "+result.Err.Error())
+	}
+	return fmt.Errorf("%d of %d volumes failed: %s", len(failed), len(r), strings.Join(messages, "; "))
+}
+
+// BulkVolumeJob is a bulk create running on the array, started by CreateVolumesBulkAsync. It holds everything
+// WaitForBulkVolumeJob needs, so it can be saved and waited for by another process.
+type BulkVolumeJob struct {
+	SymmetrixID string
+	JobID       string
+	Specs       []BulkVolumeSpec
+}
+
+// BuildCreateVolumesRequest returns the CreateVolume request for specs. The request IDs of specs are filled in.
+func BuildCreateVolumesRequest(specs []BulkVolumeSpec) (types.CreateVolumesRequest, error) {
+	req := types.CreateVolumesRequest{
+		Volumes:         make([]types.VolumeRequestParam, 0, len(specs)),
+		ExecutionOption: types.ExecutionOptionSynchronous,
+	}
+	if len(specs) == 0 {
+		return req, fmt.Errorf("at least one volume is required")
+	}
+	requestIDs := make(map[string]bool)
+	for i := range specs {
+		spec := &specs[i]
+		if spec.RequestID == "" {
+			spec.RequestID = spec.Identifier
+		}
+		if spec.RequestID == "" {
+			spec.RequestID = fmt.Sprintf("volume-%d", i)
+		}
+		if requestIDs[spec.RequestID] {
+			return req, fmt.Errorf("duplicate volume request ID %s", spec.RequestID)
+		}
+		requestIDs[spec.RequestID] = true
+		if spec.Size <= 0 {
+			return req, fmt.Errorf("volume %s: size must be positive", spec.RequestID)
+		}
+		if spec.CapacityUnit == "" {
+			spec.CapacityUnit = types.CapacityUnitGb
+		}
+		if len(spec.Identifier) > MaxVolIdentifierLength {
+			return req, fmt.Errorf("volume %s: identifier is longer than %d characters", spec.RequestID, MaxVolIdentifierLength)
+		}
+
+		param := types.VolumeRequestParam{
+			RequestID: spec.RequestID,
+			CreateNew: &types.CreateVolumeParam{
+				CreateNewFromAttributes: &types.CreateNewFromAttributes{
+					CapacityUnit: spec.CapacityUnit,
+					VolumeSize:   spec.Size,
+				},
+			},
+		}
+		if spec.Identifier != "" || spec.StorageGroupID != "" {
+			param.Actions = &types.VolumeRequestParamActions{}
+		}
+		if spec.Identifier != "" {
+			param.Actions.ManageIdentifier = &types.ManageIdentifierAction{
+				Action:     "Set",
+				Identifier: spec.Identifier,
+			}
+		}
+		if spec.StorageGroupID != "" {
+			enableMobilityID := spec.EnableMobilityID
+			param.Actions.ManageVolumeStorageGroup = &types.ManageVolumeStorageGroupAction{
+				Action:           "ADD",
+				StorageGroup:     types.VolumeStorageGroupParam{ID: spec.StorageGroupID},
+				EnableMobilityID: &enableMobilityID,
+			}
+		}
+		req.Volumes = append(req.Volumes, param)
+	}
+	return req, nil
+}
+
+// createVolumeItemError returns the error of a failed CreateVolume result item.
+func createVolumeItemError(item *types.CreateVolumeResponseItem) error {
+	if item.Messages != nil {
+		for _, m := range item.Messages.Message {
+			if m.Severity != "" && !strings.EqualFold(m.Severity, "Error") {
+				continue
+			}
+			if m.Code == "" {
+				return errors.New(m.Message)
+			}
+			return errors.New(m.Code + ": " + m.Message)
+		}
+	}
+	return fmt.Errorf("volume creation %s", item.Status)
+}
+
+// CreateVolumesBulk creates volumes with a single CreateVolume request and returns the result of each spec
+// by request ID. An error is returned only if the request as a whole failed; the failure of individual
+// volumes is reported in their results, and BulkVolumeResults.Failed gives the specs to retry.
+func (c *Client) CreateVolumesBulk(ctx context.Context, symID string, specs []BulkVolumeSpec, opts ...http.Header) (BulkVolumeResults, error) {
+	defer c.TimeSpent("CreateVolumesBulk", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	specs = append([]BulkVolumeSpec(nil), specs...)
+	req, err := BuildCreateVolumesRequest(specs)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.CreateVolume(ctx, symID, req, opts...)
+	if resp == nil {
+		return nil, err
+	}
+
+	results := make(BulkVolumeResults, len(specs))
+	for _, spec := range specs {
+		results[spec.RequestID] = &BulkVolumeResult{Spec: spec, StorageGroups: make([]string, 0)}
+	}
+	for i := range resp.Results.Result {
+		item := &resp.Results.Result[i]
+		requestID := item.RequestID
+		// Fall back to the request order if the array does not echo request IDs
+		if requestID == "" && len(resp.Results.Result) == len(specs) {
+			requestID = specs[i].RequestID
+		}
+		result := results[requestID]
+		if result == nil {
+			log.Warnf("CreateVolumesBulk: ignoring result for unknown request ID %q", item.RequestID)
+			continue
+		}
+		if item.Volume != nil {
+			result.VolumeID = item.Volume.ID
+			for _, sg := range item.Volume.StorageGroups {
+				result.StorageGroups = append(result.StorageGroups, sg.StorageGroupID)
+			}
+		}
+		if item.StorageGroup != nil && item.StorageGroup.ID != "" && !stringInSlice(item.StorageGroup.ID, result.StorageGroups) {
+			result.StorageGroups = append(result.StorageGroups, item.StorageGroup.ID)
+		}
+		switch {
+		case !strings.EqualFold(item.Status, "success"):
+			result.Err = createVolumeItemError(item)
+		case result.VolumeID == "":
+			result.Err = fmt.Errorf("no volume returned")
+		case result.Spec.StorageGroupID != "" && !stringInSlice(result.Spec.StorageGroupID, result.StorageGroups):
+			result.Err = &BulkVolumeStorageGroupError{VolumeID: result.VolumeID, StorageGroupID: result.Spec.StorageGroupID}
+		}
+	}
+	for _, result := range results {
+		if result.Err == nil && result.VolumeID == "" {
+			result.Err = fmt.Errorf("no result returned")
+		}
+	}
+	if err := results.Err(); err != nil {
+		log.Error("CreateVolumesBulk failed: " + err.Error())
+	}
+	return results, nil
+}
+
+// CreateVolumesBulkAsync submits the CreateVolume request of specs as an asynchronous job on the array and
+// returns without waiting for it. The volumes are found by identifier once the job is done, so every spec needs
+// an Identifier which is unique within the request. Use WaitForBulkVolumeJob for the results, or GetJobByID to
+// follow the job.
+func (c *Client) CreateVolumesBulkAsync(ctx context.Context, symID string, specs []BulkVolumeSpec, opts ...http.Header) (*BulkVolumeJob, error) {
+	defer c.TimeSpent("CreateVolumesBulkAsync", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	specs = append([]BulkVolumeSpec(nil), specs...)
+	req, err := BuildCreateVolumesRequest(specs)
+	if err != nil {
+		return nil, err
+	}
+	identifiers := make(map[string]bool)
+	for _, spec := range specs {
+		if spec.Identifier == "" {
+			return nil, fmt.Errorf("volume %s: an identifier is required for an asynchronous create", spec.RequestID)
+		}
+		if identifiers[spec.Identifier] {
+			return nil, fmt.Errorf("duplicate volume identifier %s", spec.Identifier)
+		}
+		identifiers[spec.Identifier] = true
+	}
+	req.ExecutionOption = types.ExecutionOptionAsynchronous
+
+	URL := RESTPrivateV1 + "systems/" + symID + "/volumes"
+	ifDebugLogPayload(req)
+	headers := c.getDefaultHeaders()
+	if len(opts) > 0 {
+		for k, vals := range opts[0] {
+			if len(vals) > 0 {
+				headers[k] = vals[0]
+			}
+		}
+	}
+	job := &types.Job{}
+	ctx, cancel := c.GetTimeoutContext(ctx)
+	defer cancel()
+	if err := c.api.Post(ctx, URL, headers, req, job); err != nil {
+		log.Error("CreateVolumesBulkAsync failed: " + err.Error())
+		return nil, err
+	}
+	if job.JobID == "" {
+		return nil, fmt.Errorf("no job returned for the asynchronous create of %d volumes", len(specs))
+	}
+	log.Infof("CreateVolumesBulkAsync started job %s for %d volumes", job.JobID, len(specs))
+	return &BulkVolumeJob{SymmetrixID: symID, JobID: job.JobID, Specs: specs}, nil
+}
+
+// WaitForBulkVolumeJob waits for a job started by CreateVolumesBulkAsync, passing each state of the job to
+// progress if it is not nil, and returns the result of each spec by request ID. Each volume is found by its
+// identifier, so volumes created before the job failed are reported too. An error is returned only if the
+// job could not be followed.
+func (c *Client) WaitForBulkVolumeJob(ctx context.Context, job *BulkVolumeJob, progress func(*types.Job)) (BulkVolumeResults, error) {
+	defer c.TimeSpent("WaitForBulkVolumeJob", time.Now())
+	if _, err := c.IsAllowedArray(job.SymmetrixID); err != nil {
+		return nil, err
+	}
+	arrayJob, err := c.waitOnJob(ctx, job.SymmetrixID, job.JobID, progress)
+	if err != nil {
+		return nil, err
+	}
+	var jobErr error
+	if arrayJob.Status != types.JobStatusSucceeded {
+		jobErr = fmt.Errorf("job %s %s: %s", job.JobID, arrayJob.Status, arrayJob.Result)
+	}
+
+	results := make(BulkVolumeResults, len(job.Specs))
+	for _, spec := range job.Specs {
+		result := &BulkVolumeResult{Spec: spec, StorageGroups: make([]string, 0)}
+		results[spec.RequestID] = result
+		volumes, err := c.GetVolumesByIdentifier(ctx, job.SymmetrixID, spec.Identifier)
+		if err != nil {
+			result.Err = err
+			continue
+		}
+		switch len(volumes.Volumes) {
+		case 0:
+			result.Err = jobErr
+			if result.Err == nil {
+				result.Err = fmt.Errorf("no volume with identifier %s", spec.Identifier)
+			}
+			continue
+		case 1:
+		default:
+			result.Err = fmt.Errorf("%d volumes have identifier %s", len(volumes.Volumes), spec.Identifier)
+			continue
+		}
+		result.VolumeID = volumes.Volumes[0].ID
+		for _, sg := range volumes.Volumes[0].StorageGroups {
+			result.StorageGroups = append(result.StorageGroups, sg.StorageGroupID)
+		}
+		if spec.StorageGroupID != "" && !stringInSlice(spec.StorageGroupID, result.StorageGroups) {
+			result.Err = &BulkVolumeStorageGroupError{VolumeID: result.VolumeID, StorageGroupID: spec.StorageGroupID}
+		}
+	}
+	if err := results.Err(); err != nil {
+		log.Error("WaitForBulkVolumeJob failed: " + err.Error())
+	}
+	return results, nil
+}
+
+// RetryFailedVolumes completes a previous CreateVolumesBulk and returns results with the retried entries replaced.
+// The volumes which were not created are created again, and the volumes which were created but not added to
+// their storage group are added to it. Volumes which were created with any other error are only reported,
+// since creating them again would leave the first volume behind.
+func (c *Client) RetryFailedVolumes(ctx context.Context, symID string, results BulkVolumeResults, opts ...http.Header) (BulkVolumeResults, error) {
+	defer c.TimeSpent("RetryFailedVolumes", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	merged := make(BulkVolumeResults, len(results))
+	for requestID, result := range results {
+		merged[requestID] = result
+	}
+	for _, result := range results.NotInStorageGroup() {
+		var sgErr *BulkVolumeStorageGroupError
+		errors.As(result.Err, &sgErr)
+		retried := *result
+		if err := c.AddVolumesToStorageGroupS(ctx, symID, sgErr.StorageGroupID, false, sgErr.VolumeID); err != nil {
+			retried.Err = &BulkVolumeStorageGroupError{VolumeID: sgErr.VolumeID, StorageGroupID: sgErr.StorageGroupID, Err: err}
+		} else {
+			retried.Err = nil
+			retried.StorageGroups = append(append([]string(nil), result.StorageGroups...), sgErr.StorageGroupID)
+		}
+		merged[result.Spec.RequestID] = &retried
+	}
+	failed := results.Failed()
+	if len(failed) == 0 {
+		return merged, nil
+	}
+	retried, err := c.CreateVolumesBulk(ctx, symID, failed, opts...)
+	if err != nil {
+		return nil, err
+	}
+	for requestID, result := range retried {
+		merged[requestID] = result
+	}
+	return merged, nil
+}
@@ -0,0 +1,215 @@
+/*
+ *
+ * Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pmax
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dell/gopowermax/v2/mock"
+	types "github.com/dell/gopowermax/v2/types/v100"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildCreateVolumesRequest(t *testing.T) {
+	specs := []BulkVolumeSpec{
+		{Identifier: "vol-a", Size: 1, StorageGroupID: "sg_1"},
+		{Size: 10, CapacityUnit: types.CapacityUnitCyl},
+	}
+	req, err := BuildCreateVolumesRequest(specs)
+	assert.NoError(t, err)
+	assert.Len(t, req.Volumes, 2)
+	assert.Equal(t, "vol-a", req.Volumes[0].RequestID)
+	assert.Equal(t, types.CapacityUnitGb, req.Volumes[0].CreateNew.CreateNewFromAttributes.CapacityUnit)
+	assert.Equal(t, "vol-a", req.Volumes[0].Actions.ManageIdentifier.Identifier)
+	assert.Equal(t, "sg_1", req.Volumes[0].Actions.ManageVolumeStorageGroup.StorageGroup.ID)
+	assert.Equal(t, "volume-1", req.Volumes[1].RequestID)
+	assert.Nil(t, req.Volumes[1].Actions)
+
+	tests := []struct {
+		name  string
+		specs []BulkVolumeSpec
+		err   string
+	}{
+		{"empty", nil, "at least one volume"},
+		{"duplicate", []BulkVolumeSpec{{Identifier: "a", Size: 1}, {Identifier: "a", Size: 1}}, "duplicate volume request ID a"},
+		{"no size", []BulkVolumeSpec{{Identifier: "a"}}, "size must be positive"},
+	}
+	for _, tt := range tests {
+		_, err := BuildCreateVolumesRequest(tt.specs)
+		assert.ErrorContains(t, err, tt.err, tt.name)
+	}
+}
+
+func TestCreateVolumesBulk(t *testing.T) {
+	c := newMockReplicationClient(t)
+	assert.NoError(t, mock.AddNewVolume("0B001", "bulk-existing", 1, mock.DefaultStorageGroup))
+
+	specs := []BulkVolumeSpec{
+		{Identifier: "bulk-new", Size: 1, StorageGroupID: mock.DefaultStorageGroup},
+		{Identifier: "bulk-existing", Size: 1, StorageGroupID: mock.DefaultStorageGroup},
+	}
+	results, err := c.CreateVolumesBulk(context.Background(), mock.DefaultSymmetrixID, specs)
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.NoError(t, results["bulk-new"].Err)
+	assert.NotEmpty(t, results["bulk-new"].VolumeID)
+	assert.Equal(t, []string{mock.DefaultStorageGroup}, results["bulk-new"].StorageGroups)
+	assert.ErrorContains(t, results["bulk-existing"].Err, "does not match existing volume size")
+	assert.Equal(t, []BulkVolumeSpec{{RequestID: "bulk-existing", Identifier: "bulk-existing", Size: 1, CapacityUnit: types.CapacityUnitGb, StorageGroupID: mock.DefaultStorageGroup}}, results.Failed())
+	assert.ErrorContains(t, results.Err(), "1 of 2 volumes failed")
+	assert.Empty(t, specs[0].RequestID, "specs of the caller are not modified")
+
+	// Once the conflicting volume is renamed the retry succeeds and the earlier success is kept
+	mock.Data.VolumeIDToVolume["0B001"].VolumeIdentifier = "bulk-renamed"
+	retried, err := c.RetryFailedVolumes(context.Background(), mock.DefaultSymmetrixID, results)
+	assert.NoError(t, err)
+	assert.NoError(t, retried.Err())
+	assert.Equal(t, results["bulk-new"], retried["bulk-new"])
+	assert.NotEmpty(t, retried["bulk-existing"].VolumeID)
+
+	_, err = c.CreateVolumesBulk(context.Background(), mock.DefaultSymmetrixID, nil)
+	assert.Error(t, err)
+
+	mock.InducedErrors.CreateVolumeError = true
+	_, err = c.CreateVolumesBulk(context.Background(), mock.DefaultSymmetrixID, []BulkVolumeSpec{{Identifier: "bulk-error", Size: 1}})
+	assert.Error(t, err)
+	mock.InducedErrors.CreateVolumeError = false
+}
+
+func TestCreateVolumesBulkAsync(t *testing.T) {
+	c := newMockReplicationClient(t)
+	defer func(sleep time.Duration) { JobRetrySleepDuration = sleep }(JobRetrySleepDuration)
+	JobRetrySleepDuration = 0
+
+	job, err := c.CreateVolumesBulkAsync(context.Background(), mock.DefaultSymmetrixID, []BulkVolumeSpec{
+		{Identifier: "bulk-async", Size: 2, StorageGroupID: mock.DefaultStorageGroup},
+		{Identifier: "bulk-async-2", Size: 1},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, mock.DefaultSymmetrixID, job.SymmetrixID)
+	assert.NotEmpty(t, job.JobID)
+
+	var states []string
+	results, err := c.WaitForBulkVolumeJob(context.Background(), job, func(arrayJob *types.Job) {
+		states = append(states, arrayJob.Status)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{types.JobStatusRunning, types.JobStatusSucceeded}, states)
+	assert.NoError(t, results.Err())
+	assert.NotEmpty(t, results["bulk-async"].VolumeID)
+	assert.Equal(t, []string{mock.DefaultStorageGroup}, results["bulk-async"].StorageGroups)
+	assert.NotEmpty(t, results["bulk-async-2"].VolumeID)
+
+	_, err = c.CreateVolumesBulkAsync(context.Background(), mock.DefaultSymmetrixID, []BulkVolumeSpec{{Identifier: "bulk-async"}})
+	assert.ErrorContains(t, err, "size must be positive")
+	_, err = c.CreateVolumesBulkAsync(context.Background(), mock.DefaultSymmetrixID, []BulkVolumeSpec{{Size: 1}})
+	assert.ErrorContains(t, err, "an identifier is required")
+	_, err = c.CreateVolumesBulkAsync(context.Background(), mock.DefaultSymmetrixID, []BulkVolumeSpec{
+		{RequestID: "a", Identifier: "same", Size: 1},
+		{RequestID: "b", Identifier: "same", Size: 1},
+	})
+	assert.ErrorContains(t, err, "duplicate volume identifier same")
+
+	mock.InducedErrors.CreateVolumeError = true
+	_, err = c.CreateVolumesBulkAsync(context.Background(), mock.DefaultSymmetrixID, []BulkVolumeSpec{{Identifier: "bulk-async-3", Size: 1}})
+	assert.Error(t, err)
+	mock.InducedErrors.CreateVolumeError = false
+
+	// a cancelled wait returns without waiting out the retries
+	job, err = c.CreateVolumesBulkAsync(context.Background(), mock.DefaultSymmetrixID, []BulkVolumeSpec{{Identifier: "bulk-async-4", Size: 1}})
+	assert.NoError(t, err)
+	JobRetrySleepDuration = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err = c.WaitForBulkVolumeJob(ctx, job, func(*types.Job) { cancel() })
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+// TestRetryFailedVolumesStorageGroup creates a volume which the array does not add to its storage group.
+// The retry only adds the volume to the storage group, and never creates it again.
+func TestRetryFailedVolumesStorageGroup(t *testing.T) {
+	symID := "000000000001"
+	var creates, adds int
+	addFails := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/volumes"):
+			creates++
+			_ = json.NewEncoder(w).Encode(&types.CreateVolumesResponse{
+				Summary: types.ResponseSummary{Total: 2, Succeeded: 1, Failed: 1},
+				Results: types.CreateVolumesResults{Result: []types.CreateVolumeResponseItem{
+					{RequestID: "vol-sg", Status: "success", Volume: &types.VolumeRefResponse{ID: "00001"}},
+					{RequestID: "vol-failed", Status: "failed"},
+				}},
+			})
+		case r.Method == http.MethodPut && strings.HasSuffix(r.URL.Path, XStorageGroup+"/sg_1"):
+			adds++
+			if addFails {
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte(`{"message":"add failed"}`))
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"not found"}`))
+		}
+	}))
+	defer server.Close()
+	c, err := NewClientWithArgs(server.URL, "", true, true, "")
+	assert.NoError(t, err)
+	c.SetAllowedArrays([]string{symID})
+
+	results, err := c.CreateVolumesBulk(context.Background(), symID, []BulkVolumeSpec{
+		{Identifier: "vol-sg", Size: 1, StorageGroupID: "sg_1"},
+		{Identifier: "vol-failed", Size: 1},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "00001", results["vol-sg"].VolumeID)
+	var sgErr *BulkVolumeStorageGroupError
+	assert.ErrorAs(t, results["vol-sg"].Err, &sgErr)
+	assert.Equal(t, "sg_1", sgErr.StorageGroupID)
+	assert.Len(t, results.NotInStorageGroup(), 1)
+	if assert.Len(t, results.Failed(), 1) {
+		assert.Equal(t, "vol-failed", results.Failed()[0].RequestID)
+	}
+	assert.ErrorContains(t, results.Err(), "2 of 2 volumes failed")
+
+	// the add to the storage group fails again and only the volume which was not created is created again
+	retried, err := c.RetryFailedVolumes(context.Background(), symID, results)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, creates)
+	assert.Equal(t, 1, adds)
+	assert.Equal(t, "00001", retried["vol-sg"].VolumeID)
+	assert.ErrorAs(t, retried["vol-sg"].Err, &sgErr)
+	assert.ErrorContains(t, sgErr, "add failed")
+	assert.Error(t, results["vol-sg"].Err, "the results passed in are not modified")
+
+	addFails = false
+	retried, err = c.RetryFailedVolumes(context.Background(), symID, BulkVolumeResults{"vol-sg": retried["vol-sg"]})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, creates)
+	assert.Equal(t, 2, adds)
+	assert.NoError(t, retried.Err())
+	assert.Equal(t, []string{"sg_1"}, retried["vol-sg"].StorageGroups)
+}
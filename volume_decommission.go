@@ -0,0 +1,186 @@
+/*
+ Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pmax
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+	log "github.com/sirupsen/logrus"
+)
+
+// Stages of DecommissionVolume, in the order they are run
+const (
+	DecommissionStageCheck               = "check"
+	DecommissionStageRemoveStorageGroups = "remove-storage-groups"
+	DecommissionStageSnapshots           = "snapshots"
+	DecommissionStageDeallocate          = "deallocate"
+	DecommissionStageDelete              = "delete"
+)
+
+// VolumeDecommissionError reports the stage of DecommissionVolume which failed.
+// Running DecommissionVolume again resumes from that stage.
+type VolumeDecommissionError struct {
+	Stage       string
+	SymmetrixID string
+	VolumeID    string
+	Err         error
+}
+
+func (e *VolumeDecommissionError) Error() string {
+	return fmt.Sprintf("decommission of volume %s on %s failed at stage %s: %s", e.VolumeID, e.SymmetrixID, e.Stage, e.Err.Error())
+}
+
+func (e *VolumeDecommissionError) Unwrap() error {
+	return e.Err
+}
+
+// VolumeDecommission is the result of DecommissionVolume.
+type VolumeDecommission struct {
+	SymmetrixID string
+	VolumeID    string
+	// AlreadyDeleted is set if the volume did not exist when DecommissionVolume was called.
+	AlreadyDeleted bool
+	// MaskingViews are the masking views the volume was in.
+	MaskingViews []string
+	// RemovedFromStorageGroups are the storage groups the volume was removed from.
+	RemovedFromStorageGroups []string
+	// UnlinkedSnapshots are the snapshot links removed, as snapshot:generation:source->target.
+	UnlinkedSnapshots []string
+	// DeletedSnapshots are the snapshots of the volume deleted, as snapshot:generation.
+	DeletedSnapshots []string
+	// Completed are the stages which were run, in order.
+	Completed []string
+}
+
+// DecommissionVolume removes a volume from all its storage groups, unlinks its snapshot links, deletes its snapshots,
+// frees its tracks and deletes it. Each stage only acts on what is left to do, so DecommissionVolume can be run
+// again after a failure and succeeds if the volume no longer exists. A volume in a masking view is refused unless
+// force is set. A *VolumeDecommissionError tells which stage failed.
+func (c *Client) DecommissionVolume(ctx context.Context, symID string, volumeID string, force bool) (*VolumeDecommission, error) {
+	defer c.TimeSpent("DecommissionVolume", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	result := &VolumeDecommission{
+		SymmetrixID:              symID,
+		VolumeID:                 volumeID,
+		MaskingViews:             make([]string, 0),
+		RemovedFromStorageGroups: make([]string, 0),
+		UnlinkedSnapshots:        make([]string, 0),
+		DeletedSnapshots:         make([]string, 0),
+		Completed:                make([]string, 0),
+	}
+	fail := func(stage string, err error) (*VolumeDecommission, error) {
+		log.Error("DecommissionVolume failed: " + err.Error())
+		return result, &VolumeDecommissionError{Stage: stage, SymmetrixID: symID, VolumeID: volumeID, Err: err}
+	}
+
+	volume, err := c.GetVolumeByID(ctx, symID, volumeID)
+	if err != nil {
+		if types.IsNotFoundError(err) {
+			result.AlreadyDeleted = true
+			return result, nil
+		}
+		return fail(DecommissionStageCheck, err)
+	}
+	for _, storageGroupID := range volume.StorageGroupIDList {
+		storageGroup, err := c.GetStorageGroup(ctx, symID, storageGroupID)
+		if err != nil {
+			return fail(DecommissionStageCheck, err)
+		}
+		result.MaskingViews = append(result.MaskingViews, storageGroup.MaskingView...)
+	}
+	if len(result.MaskingViews) > 0 && !force {
+		return fail(DecommissionStageCheck, fmt.Errorf("volume is masked in %v", result.MaskingViews))
+	}
+	result.Completed = append(result.Completed, DecommissionStageCheck)
+
+	for _, storageGroupID := range volume.StorageGroupIDList {
+		if _, err := c.RemoveVolumesFromStorageGroup(ctx, symID, storageGroupID, force, volumeID); err != nil {
+			return fail(DecommissionStageRemoveStorageGroups, err)
+		}
+		result.RemovedFromStorageGroups = append(result.RemovedFromStorageGroups, storageGroupID)
+	}
+	result.Completed = append(result.Completed, DecommissionStageRemoveStorageGroups)
+
+	if volume.SnapSource || volume.SnapTarget {
+		if err := c.decommissionSnapshots(ctx, symID, volumeID, result); err != nil {
+			return fail(DecommissionStageSnapshots, err)
+		}
+	}
+	result.Completed = append(result.Completed, DecommissionStageSnapshots)
+
+	job, err := c.InitiateDeallocationOfTracksFromVolume(ctx, symID, volumeID)
+	if err != nil {
+		return fail(DecommissionStageDeallocate, err)
+	}
+	job, err = c.WaitOnJobCompletion(ctx, symID, job.JobID)
+	if err != nil {
+		return fail(DecommissionStageDeallocate, err)
+	}
+	if job.Status == types.JobStatusFailed {
+		return fail(DecommissionStageDeallocate, fmt.Errorf("job %s failed: %s", job.JobID, job.Result))
+	}
+	result.Completed = append(result.Completed, DecommissionStageDeallocate)
+
+	if err := c.DeleteVolume(ctx, symID, volumeID); err != nil && !types.IsNotFoundError(err) {
+		return fail(DecommissionStageDelete, err)
+	}
+	result.Completed = append(result.Completed, DecommissionStageDelete)
+	return result, nil
+}
+
+// decommissionSnapshots unlinks the volume from the snapshots it is a target of, unlinks the targets of its own
+// snapshots and deletes them.
+func (c *Client) decommissionSnapshots(ctx context.Context, symID string, volumeID string, result *VolumeDecommission) error {
+	snapInfo, err := c.GetVolumeSnapInfo(ctx, symID, volumeID)
+	if err != nil {
+		return err
+	}
+	for _, link := range snapInfo.VolumeSnapshotLink {
+		if !link.Linked && !link.Defined {
+			continue
+		}
+		err := c.ModifySnapshotS(ctx, symID, []types.VolumeList{{Name: link.LinkSource}}, []types.VolumeList{{Name: volumeID}},
+			link.SnapshotName, string(Unlink), "", link.Generation, false)
+		if err != nil {
+			return err
+		}
+		result.UnlinkedSnapshots = append(result.UnlinkedSnapshots, fmt.Sprintf("%s:%d:%s->%s", link.SnapshotName, link.Generation, link.LinkSource, volumeID))
+	}
+
+	// Deleting a generation renumbers the older ones, so the oldest (highest numbered) are deleted first
+	sources := snapInfo.VolumeSnapshotSource
+	sort.SliceStable(sources, func(i, j int) bool { return sources[i].Generation > sources[j].Generation })
+	for _, source := range sources {
+		for _, linked := range source.LinkedVolumes {
+			err := c.ModifySnapshotS(ctx, symID, []types.VolumeList{{Name: volumeID}}, []types.VolumeList{{Name: linked.TargetDevice}},
+				source.SnapshotName, string(Unlink), "", source.Generation, false)
+			if err != nil {
+				return err
+			}
+			result.UnlinkedSnapshots = append(result.UnlinkedSnapshots, fmt.Sprintf("%s:%d:%s->%s", source.SnapshotName, source.Generation, volumeID, linked.TargetDevice))
+		}
+		if err := c.DeleteSnapshotS(ctx, symID, source.SnapshotName, []types.VolumeList{{Name: volumeID}}, source.Generation); err != nil {
+			return err
+		}
+		result.DeletedSnapshots = append(result.DeletedSnapshots, fmt.Sprintf("%s:%d", source.SnapshotName, source.Generation))
+	}
+	return nil
+}
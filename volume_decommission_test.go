@@ -0,0 +1,76 @@
+/*
+ *
+ * Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pmax
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dell/gopowermax/v2/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecommissionVolume(t *testing.T) {
+	sleep := JobRetrySleepDuration
+	JobRetrySleepDuration = 0
+	defer func() { JobRetrySleepDuration = sleep }()
+	allStages := []string{DecommissionStageCheck, DecommissionStageRemoveStorageGroups, DecommissionStageSnapshots, DecommissionStageDeallocate, DecommissionStageDelete}
+
+	c := newMockReplicationClient(t)
+	ctx := context.Background()
+	_, err := mock.AddStorageGroup("decom-masked-sg", "SRP_1", "Diamond")
+	assert.NoError(t, err)
+	_, err = mock.AddMaskingView("decom-mv", "decom-masked-sg", "CSI-Test-Node-1-ISCSI", "iscsi_ports")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.AddNewVolume("0D001", "decom-masked", 1, "decom-masked-sg"))
+	assert.NoError(t, mock.AddNewVolume("0D002", "decom-snap", 1, "CSI-Test-SG-2"))
+	mock.AddNewSnapshot("0D002", "decom-snapshot")
+
+	// A masked volume is refused unless forced
+	result, err := c.DecommissionVolume(ctx, mock.DefaultSymmetrixID, "0D001", false)
+	var decommissionErr *VolumeDecommissionError
+	assert.True(t, errors.As(err, &decommissionErr))
+	assert.Equal(t, DecommissionStageCheck, decommissionErr.Stage)
+	assert.Equal(t, []string{"decom-mv"}, result.MaskingViews)
+	assert.Empty(t, result.Completed)
+
+	result, err = c.DecommissionVolume(ctx, mock.DefaultSymmetrixID, "0D001", true)
+	assert.NoError(t, err)
+	assert.Equal(t, allStages, result.Completed)
+	assert.Equal(t, []string{"decom-masked-sg"}, result.RemovedFromStorageGroups)
+
+	// A failed stage is reported and a second run resumes from it
+	mock.InducedErrors.DeleteSnapshotError = true
+	result, err = c.DecommissionVolume(ctx, mock.DefaultSymmetrixID, "0D002", false)
+	assert.True(t, errors.As(err, &decommissionErr))
+	assert.Equal(t, DecommissionStageSnapshots, decommissionErr.Stage)
+	assert.Equal(t, []string{"CSI-Test-SG-2"}, result.RemovedFromStorageGroups)
+	mock.InducedErrors.DeleteSnapshotError = false
+
+	result, err = c.DecommissionVolume(ctx, mock.DefaultSymmetrixID, "0D002", false)
+	assert.NoError(t, err)
+	assert.Equal(t, allStages, result.Completed)
+	assert.Empty(t, result.RemovedFromStorageGroups)
+	assert.Equal(t, []string{"decom-snapshot:0"}, result.DeletedSnapshots)
+
+	result, err = c.DecommissionVolume(ctx, mock.DefaultSymmetrixID, "0D002", false)
+	assert.NoError(t, err)
+	assert.True(t, result.AlreadyDeleted)
+}
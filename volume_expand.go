@@ -0,0 +1,213 @@
+/*
+ Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pmax
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+	log "github.com/sirupsen/logrus"
+)
+
+// Sides of a volume expansion
+const (
+	ExpansionSideLocal = "local"
+	ExpansionSideR1    = "R1"
+	ExpansionSideR2    = "R2"
+)
+
+var (
+	// ExpansionPollCount is how many times ExpandVolumeSafe reads the volumes while waiting for the new size.
+	ExpansionPollCount = 20
+	// ExpansionPollInterval is the time between reads of the volumes while waiting for the new size.
+	ExpansionPollInterval = 3 * time.Second
+)
+
+// unhealthyRDFPairStates are the pair states in which the R2 side cannot be expanded.
+var unhealthyRDFPairStates = []string{"Partitioned", "Invalid", "TransIdle"}
+
+// VolumeExpansionError reports the side of an expansion which failed.
+type VolumeExpansionError struct {
+	// Side is ExpansionSideLocal, ExpansionSideR1 or ExpansionSideR2.
+	Side        string
+	SymmetrixID string
+	VolumeID    string
+	Err         error
+}
+
+func (e *VolumeExpansionError) Error() string {
+	return fmt.Sprintf("expansion of %s volume %s on %s failed: %s", e.Side, e.VolumeID, e.SymmetrixID, e.Err.Error())
+}
+
+func (e *VolumeExpansionError) Unwrap() error {
+	return e.Err
+}
+
+// VolumeExpansion is the result of ExpandVolumeSafe.
+type VolumeExpansion struct {
+	SymmetrixID string
+	VolumeID    string
+	CapacityGB  float64
+	// The remaining fields are only set for a volume in an SRDF pair.
+	RDFMode           string
+	RDFGroupNumber    int
+	RemoteSymmetrixID string
+	RemoteVolumeID    string
+	RemoteCapacityGB  float64
+}
+
+// expansionSide is one volume of an expansion.
+type expansionSide struct {
+	side        string
+	symID       string
+	volumeID    string
+	rdfGroupNum int
+}
+
+func (s expansionSide) fail(err error) error {
+	return &VolumeExpansionError{Side: s.side, SymmetrixID: s.symID, VolumeID: s.volumeID, Err: err}
+}
+
+// checkExpandable returns an error if volume cannot be expanded to sizeGB.
+func checkExpandable(volume *types.Volume, sizeGB float64) error {
+	if volume.SnapTarget {
+		return fmt.Errorf("volume is a SnapVX link target")
+	}
+	if sizeGB <= volume.CapacityGB {
+		return fmt.Errorf("new size %v GB is not larger than the current size %v GB", sizeGB, volume.CapacityGB)
+	}
+	return nil
+}
+
+// capacityMatches reports whether a volume capacity is sizeGB, allowing for the rounding of cap_gb.
+func capacityMatches(capacityGB, sizeGB float64) bool {
+	return math.Abs(capacityGB-sizeGB) < 0.01
+}
+
+// ExpandVolumeSafe expands a volume, and its SRDF partner if it is in an SRDF pair, to sizeGB and waits until
+// both sides report the new size. The volume must be the R1 of a pair. Before anything is changed it checks that
+// neither side is a SnapVX link target, that the sides are the same size and that the pair is not partitioned.
+// A pair is expanded in one request to the R1 naming its RDF group, which grows both sides in every RDF mode.
+// A *VolumeExpansionError tells which side failed; if the sizes were not reached in time it is returned with
+// the result holding the last capacities read, and if ctx is done first it holds the error of ctx.
+func (c *Client) ExpandVolumeSafe(ctx context.Context, symID string, volumeID string, sizeGB float64) (*VolumeExpansion, error) {
+	defer c.TimeSpent("ExpandVolumeSafe", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	local := expansionSide{side: ExpansionSideLocal, symID: symID, volumeID: volumeID}
+	volume, err := c.GetVolumeByID(ctx, symID, volumeID)
+	if err != nil {
+		return nil, local.fail(err)
+	}
+	result := &VolumeExpansion{SymmetrixID: symID, VolumeID: volumeID, CapacityGB: volume.CapacityGB}
+	if len(volume.RDFGroupIDList) > 0 {
+		local.side = ExpansionSideR1
+	}
+	if err := checkExpandable(volume, sizeGB); err != nil {
+		return nil, local.fail(err)
+	}
+	size := strconv.FormatFloat(sizeGB, 'f', -1, 64)
+
+	if local.side == ExpansionSideLocal {
+		if _, err := c.ExpandVolume(ctx, symID, volumeID, 0, size, types.CapacityUnitGb); err != nil {
+			return nil, local.fail(err)
+		}
+		return result, c.waitForExpansion(ctx, sizeGB, result, local)
+	}
+
+	local.rdfGroupNum = volume.RDFGroupIDList[0].RDFGroupNumber
+	pair, err := c.GetRDFDevicePairInfo(ctx, symID, strconv.Itoa(local.rdfGroupNum), volumeID)
+	if err != nil {
+		return nil, local.fail(err)
+	}
+	if !strings.Contains(pair.VolumeConfig, "RDF1") {
+		return nil, local.fail(fmt.Errorf("volume is %s, an SRDF pair must be expanded from the R1 side", pair.VolumeConfig))
+	}
+	if stringInSlice(pair.RdfpairState, unhealthyRDFPairStates) {
+		return nil, local.fail(fmt.Errorf("SRDF pair state is %s", pair.RdfpairState))
+	}
+	remote := expansionSide{side: ExpansionSideR2, symID: pair.RemoteSymmID, volumeID: pair.RemoteVolumeName}
+	result.RDFMode = pair.RdfMode
+	result.RDFGroupNumber = local.rdfGroupNum
+	result.RemoteSymmetrixID = remote.symID
+	result.RemoteVolumeID = remote.volumeID
+	remoteVolume, err := c.GetVolumeByID(ctx, remote.symID, remote.volumeID)
+	if err != nil {
+		return nil, remote.fail(err)
+	}
+	result.RemoteCapacityGB = remoteVolume.CapacityGB
+	if err := checkExpandable(remoteVolume, sizeGB); err != nil {
+		return nil, remote.fail(err)
+	}
+	if !capacityMatches(remoteVolume.CapacityGB, volume.CapacityGB) {
+		return nil, remote.fail(fmt.Errorf("R2 size %v GB differs from R1 size %v GB", remoteVolume.CapacityGB, volume.CapacityGB))
+	}
+
+	log.Infof("expanding R1 %s on %s and R2 %s on %s through RDF group %d (%s)",
+		volumeID, symID, remote.volumeID, remote.symID, local.rdfGroupNum, pair.RdfMode)
+	if _, err := c.ExpandVolume(ctx, symID, volumeID, local.rdfGroupNum, size, types.CapacityUnitGb); err != nil {
+		return nil, local.fail(err)
+	}
+	return result, c.waitForExpansion(ctx, sizeGB, result, local, remote)
+}
+
+// waitForExpansion polls the volumes of sides until they all report sizeGB, updating the capacities of result.
+// The error names the first side which has not reached the size.
+func (c *Client) waitForExpansion(ctx context.Context, sizeGB float64, result *VolumeExpansion, sides ...expansionSide) error {
+	var pending expansionSide
+	var err error
+	for i := 0; i < ExpansionPollCount; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return pending.fail(ctx.Err())
+			case <-time.After(ExpansionPollInterval):
+			}
+		}
+		err = nil
+		done := true
+		for _, side := range sides {
+			var volume *types.Volume
+			volume, err = c.GetVolumeByID(ctx, side.symID, side.volumeID)
+			if err != nil {
+				pending = side
+				done = false
+				break
+			}
+			if side.side == ExpansionSideR2 {
+				result.RemoteCapacityGB = volume.CapacityGB
+			} else {
+				result.CapacityGB = volume.CapacityGB
+			}
+			if done && !capacityMatches(volume.CapacityGB, sizeGB) {
+				pending = side
+				done = false
+			}
+		}
+		if done {
+			return nil
+		}
+	}
+	if err == nil {
+		err = fmt.Errorf("size did not reach %v GB after %d checks", sizeGB, ExpansionPollCount)
+	}
+	return pending.fail(err)
+}
@@ -0,0 +1,231 @@
+/*
+ *
+ * Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pmax
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	expandR1Array = "000000000001"
+	expandR2Array = "000000000002"
+)
+
+// fakeExpandArrays serves volume 00001 on both arrays as an SRDF pair in group 10 with the given mode, and
+// the unreplicated volume 00002 on the R1 array. As on an array, an expansion naming the RDF group grows both
+// sides of the pair, whatever the mode. Expansions are recorded as array:volume:rdfGroupNumber.
+type fakeExpandArrays struct {
+	volumes map[string]*types.Volume
+	mode    string
+	// failExpand makes expansion of array:volume fail; ignoreExpand leaves the size of array:volume unchanged
+	// by an expansion which succeeds.
+	failExpand   map[string]bool
+	ignoreExpand map[string]bool
+	expansions   []string
+}
+
+func newFakeExpandArrays(mode string) *fakeExpandArrays {
+	rdf := []types.RDFGroupID{{RDFGroupNumber: 10}}
+	return &fakeExpandArrays{
+		volumes: map[string]*types.Volume{
+			expandR1Array + ":00001": {VolumeID: "00001", CapacityGB: 10, RDFGroupIDList: rdf},
+			expandR2Array + ":00001": {VolumeID: "00001", CapacityGB: 10, RDFGroupIDList: rdf},
+			expandR1Array + ":00002": {VolumeID: "00002", CapacityGB: 10},
+		},
+		mode:         mode,
+		failExpand:   make(map[string]bool),
+		ignoreExpand: make(map[string]bool),
+	}
+}
+
+func (f *fakeExpandArrays) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	parts := strings.Split(r.URL.Path, "/")
+	last := parts[len(parts)-1]
+	symID := ""
+	for i, part := range parts {
+		if part == "symmetrix" && i+1 < len(parts) {
+			symID = parts[i+1]
+		}
+	}
+	key := symID + ":" + last
+	var out interface{}
+	switch {
+	case strings.Contains(r.URL.Path, "/rdf_group/"):
+		config, remote := "RDF1+TDEV", expandR2Array
+		if symID == expandR2Array {
+			config, remote = "RDF2+TDEV", expandR1Array
+		}
+		out = &types.RDFDevicePair{
+			LocalSymmID: symID, RemoteSymmID: remote, LocalVolumeName: last, RemoteVolumeName: last,
+			LocalRdfGroupNumber: 10, RemoteRdfGroupNumber: 10, VolumeConfig: config, RdfMode: f.mode, RdfpairState: "Synchronized",
+		}
+	case f.volumes[key] != nil && r.Method == http.MethodPut:
+		payload := &types.EditVolumeParam{}
+		_ = json.NewDecoder(r.Body).Decode(payload)
+		expand := payload.EditVolumeActionParam.ExpandVolumeParam
+		f.expansions = append(f.expansions, key+":"+strconv.Itoa(expand.RDFGroupNumber))
+		if f.failExpand[key] {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message":"expand failed"}`))
+			return
+		}
+		size, _ := strconv.ParseFloat(expand.VolumeAttribute.VolumeSize, 64)
+		grow := []string{key}
+		if expand.RDFGroupNumber > 0 {
+			grow = append(grow, expandR2Array+":"+last)
+		}
+		for _, side := range grow {
+			if !f.ignoreExpand[side] {
+				f.volumes[side].CapacityGB = size
+			}
+		}
+		out = f.volumes[key]
+	case f.volumes[key] != nil:
+		out = f.volumes[key]
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"not found"}`))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func TestExpandVolumeSafe(t *testing.T) {
+	pollCount, pollInterval := ExpansionPollCount, ExpansionPollInterval
+	ExpansionPollCount, ExpansionPollInterval = 2, 0
+	defer func() { ExpansionPollCount, ExpansionPollInterval = pollCount, pollInterval }()
+
+	tests := []struct {
+		name           string
+		mode           string
+		volumeID       string
+		sizeGB         float64
+		setup          func(f *fakeExpandArrays)
+		wantExpansions []string
+		wantSide       string
+		wantErr        string
+	}{
+		{
+			name: "unreplicated", volumeID: "00002", sizeGB: 20,
+			wantExpansions: []string{expandR1Array + ":00002:0"},
+		},
+		{
+			name: "synchronous expands through R1", mode: "Synchronous", volumeID: "00001", sizeGB: 20,
+			wantExpansions: []string{expandR1Array + ":00001:10"},
+		},
+		{
+			name: "asynchronous expands through R1", mode: "Asynchronous", volumeID: "00001", sizeGB: 20,
+			wantExpansions: []string{expandR1Array + ":00001:10"},
+		},
+		{
+			name: "metro expands through R1", mode: "Active", volumeID: "00001", sizeGB: 20,
+			wantExpansions: []string{expandR1Array + ":00001:10"},
+		},
+		{
+			name: "not larger", mode: "Synchronous", volumeID: "00001", sizeGB: 10,
+			wantSide: ExpansionSideR1, wantErr: "not larger",
+		},
+		{
+			name: "R2 is a link target", mode: "Synchronous", volumeID: "00001", sizeGB: 20,
+			setup:    func(f *fakeExpandArrays) { f.volumes[expandR2Array+":00001"].SnapTarget = true },
+			wantSide: ExpansionSideR2, wantErr: "SnapVX link target",
+		},
+		{
+			name: "sides differ", mode: "Synchronous", volumeID: "00001", sizeGB: 20,
+			setup:    func(f *fakeExpandArrays) { f.volumes[expandR2Array+":00001"].CapacityGB = 12 },
+			wantSide: ExpansionSideR2, wantErr: "differs from R1 size",
+		},
+		{
+			name: "expansion fails", mode: "Asynchronous", volumeID: "00001", sizeGB: 20,
+			setup:          func(f *fakeExpandArrays) { f.failExpand[expandR1Array+":00001"] = true },
+			wantExpansions: []string{expandR1Array + ":00001:10"},
+			wantSide:       ExpansionSideR1, wantErr: "expand failed",
+		},
+		{
+			name: "R2 size not reached", mode: "Asynchronous", volumeID: "00001", sizeGB: 20,
+			setup:          func(f *fakeExpandArrays) { f.ignoreExpand[expandR2Array+":00001"] = true },
+			wantExpansions: []string{expandR1Array + ":00001:10"},
+			wantSide:       ExpansionSideR2, wantErr: "did not reach 20 GB",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arrays := newFakeExpandArrays(tt.mode)
+			if tt.setup != nil {
+				tt.setup(arrays)
+			}
+			server := httptest.NewServer(arrays)
+			defer server.Close()
+			c, err := NewClientWithArgs(server.URL, "", true, true, "")
+			assert.NoError(t, err)
+			c.SetAllowedArrays([]string{expandR1Array, expandR2Array})
+
+			result, err := c.ExpandVolumeSafe(context.Background(), expandR1Array, tt.volumeID, tt.sizeGB)
+			assert.Equal(t, tt.wantExpansions, arrays.expansions)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				var expansionErr *VolumeExpansionError
+				assert.True(t, errors.As(err, &expansionErr))
+				assert.Equal(t, tt.wantSide, expansionErr.Side)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.sizeGB, result.CapacityGB)
+			if tt.mode != "" {
+				assert.Equal(t, tt.mode, result.RDFMode)
+				assert.Equal(t, expandR2Array, result.RemoteSymmetrixID)
+				assert.Equal(t, tt.sizeGB, result.RemoteCapacityGB)
+			}
+		})
+	}
+}
+
+func TestExpandVolumeSafeCancelled(t *testing.T) {
+	pollCount, pollInterval := ExpansionPollCount, ExpansionPollInterval
+	ExpansionPollCount, ExpansionPollInterval = 20, time.Hour
+	defer func() { ExpansionPollCount, ExpansionPollInterval = pollCount, pollInterval }()
+
+	arrays := newFakeExpandArrays("Synchronous")
+	arrays.ignoreExpand[expandR2Array+":00001"] = true
+	server := httptest.NewServer(arrays)
+	defer server.Close()
+	c, err := NewClientWithArgs(server.URL, "", true, true, "")
+	assert.NoError(t, err)
+	c.SetAllowedArrays([]string{expandR1Array, expandR2Array})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = c.ExpandVolumeSafe(ctx, expandR1Array, "00001", 20)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	var expansionErr *VolumeExpansionError
+	assert.ErrorAs(t, err, &expansionErr)
+	assert.Equal(t, ExpansionSideR2, expansionErr.Side)
+}
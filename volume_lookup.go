@@ -0,0 +1,235 @@
+/*
+ Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pmax
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+	log "github.com/sirupsen/logrus"
+)
+
+var deviceIdentifierRegex = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// devicePathPrefixes are the /dev/disk/by-id name prefixes in front of a volume WWN or NGUID.
+// scsi-3 and dm-uuid-mpath-3 carry the NAA type 3 designator prefix.
+var devicePathPrefixes = []string{"wwn-0x", "wwn-", "scsi-3", "dm-uuid-mpath-3", "nvme-eui.", "eui.", "naa.", "0x"}
+
+// DeviceVolume is a device path resolved by GetVolumesByDevicePaths.
+type DeviceVolume struct {
+	DevicePath string
+	// Identifier is the WWN or NGUID of the device path as 32 lower case hex digits.
+	Identifier string
+	// VolumeID is empty if no volume has the identifier.
+	VolumeID string
+}
+
+// deviceIdentifier returns the WWN or NGUID of a device, given as a /dev/disk/by-id path or name
+// (e.g. wwn-0x6000..., scsi-36000..., dm-uuid-mpath-36000... or nvme-eui.6000...) or as a bare identifier.
+func deviceIdentifier(devicePath string) (string, error) {
+	name := strings.TrimSpace(devicePath)
+	name = strings.ToLower(name[strings.LastIndex(name, "/")+1:])
+	for _, prefix := range devicePathPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			name = strings.TrimPrefix(name, prefix)
+			break
+		}
+	}
+	name = normalizeNGUID(name)
+	if !deviceIdentifierRegex.MatchString(name) {
+		return "", fmt.Errorf("%q is not a volume WWN or NGUID", devicePath)
+	}
+	return name, nil
+}
+
+// volumeHasIdentifier reports whether the effective WWN, WWN, encapsulated WWN or NGUID of a volume is identifier.
+func volumeHasIdentifier(volume *types.Volume, identifier string) bool {
+	for _, volumeIdentifier := range []string{volume.EffectiveWWN, volume.WWN, volume.EncapsulatedWWN, volume.NGUID} {
+		if volumeIdentifier != "" && normalizeNGUID(volumeIdentifier) == identifier {
+			return true
+		}
+	}
+	return false
+}
+
+// findVolumeByIdentifier returns the volume found by querying each of the volume iterator params with identifier,
+// or nil if there is none.
+func (c *Client) findVolumeByIdentifier(ctx context.Context, symID string, identifier string, params []string) (*types.Volume, error) {
+	for _, param := range params {
+		volumeIDs, err := c.GetVolumeIDListWithParams(ctx, symID, map[string]string{param: identifier})
+		if err != nil {
+			return nil, err
+		}
+		for _, volumeID := range volumeIDs {
+			volume, err := c.GetVolumeByID(ctx, symID, volumeID)
+			if err != nil {
+				return nil, err
+			}
+			if volumeHasIdentifier(volume, identifier) {
+				return volume, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// GetVolumeByWWN returns the volume with the given WWN, which is matched against the effective WWN
+// (the WWN presented to hosts), the native WWN and the encapsulated WWN of the volume.
+// The WWN may be given as a /dev/disk/by-id name such as wwn-0x6000... or scsi-36000....
+func (c *Client) GetVolumeByWWN(ctx context.Context, symID string, wwn string) (*types.Volume, error) {
+	defer c.TimeSpent("GetVolumeByWWN", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	identifier, err := deviceIdentifier(wwn)
+	if err != nil {
+		return nil, err
+	}
+	volume, err := c.findVolumeByIdentifier(ctx, symID, identifier, []string{"effective_wwn", "wwn", "encapsulated_wwn"})
+	if err != nil {
+		return nil, err
+	}
+	if volume == nil {
+		return nil, fmt.Errorf("no volume found with WWN %s", wwn)
+	}
+	return volume, nil
+}
+
+// DevicePathBulkLookupThreshold is the number of device paths above which GetVolumesByDevicePaths reads the
+// identifiers of every volume on the array in one paged query, instead of querying for each identifier.
+var DevicePathBulkLookupThreshold = 50
+
+// volumeIdentifierFields are the volume fields a WWN or NGUID from a host is matched against, in order of preference.
+var volumeIdentifierFields = []string{"effective_wwn", "wwn", "encapsulated_wwn", "nguid"}
+
+// volumeIdentifiersURL returns the v1 volumes query selecting the ID, WWNs and NGUID of the volumes matching filter,
+// or of every volume if filter is empty.
+func (c *Client) volumeIdentifiersURL(symID string, filter string) string {
+	URL := c.urlPrefixV1() + symID + XVolumeV1 + SelectQuery + SelectID + SelectWWN +
+		SelectEffectiveWWN + SelectEncapsulatedWWN + SelectNGUID
+	if filter != "" {
+		URL += "&filter=" + filter
+	}
+	return URL + "&limit=1000&expiration_delay_secs=30"
+}
+
+// volumeEnhancedIdentifiers returns the effective WWN, WWN, encapsulated WWN and NGUID of a volume in the form of deviceIdentifier.
+func volumeEnhancedIdentifiers(volume *types.VolumeEnhanced) []string {
+	identifiers := make([]string, 0, len(volumeIdentifierFields))
+	for _, identifier := range []string{volume.EffectiveWWN, volume.WWN, volume.EncapsulatedWWN, volume.NGUID} {
+		if identifier != "" {
+			identifiers = append(identifiers, normalizeNGUID(identifier))
+		}
+	}
+	return identifiers
+}
+
+// resolveDevicesFiltered sets the VolumeID of each device with a filtered v1 volumes query per identifier field.
+func (c *Client) resolveDevicesFiltered(ctx context.Context, symID string, devices []DeviceVolume) error {
+	for i := range devices {
+		for _, field := range volumeIdentifierFields {
+			volumes, err := c.getVolumesV1(ctx, c.volumeIdentifiersURL(symID, field+"%20EQ%20"+devices[i].Identifier))
+			if err != nil {
+				return err
+			}
+			for j := range volumes {
+				if stringInSlice(devices[i].Identifier, volumeEnhancedIdentifiers(&volumes[j])) {
+					devices[i].VolumeID = volumes[j].ID
+					break
+				}
+			}
+			if devices[i].VolumeID != "" {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// resolveDevicesBulk sets the VolumeID of each device from the identifiers of every volume on the array.
+func (c *Client) resolveDevicesBulk(ctx context.Context, symID string, devices []DeviceVolume) error {
+	volumes, err := c.getVolumesV1(ctx, c.volumeIdentifiersURL(symID, ""))
+	if err != nil {
+		return err
+	}
+	volumeIDs := make(map[string]string)
+	for i := range volumes {
+		for _, identifier := range volumeEnhancedIdentifiers(&volumes[i]) {
+			if _, ok := volumeIDs[identifier]; !ok {
+				volumeIDs[identifier] = volumes[i].ID
+			}
+		}
+	}
+	for i := range devices {
+		devices[i].VolumeID = volumeIDs[devices[i].Identifier]
+	}
+	return nil
+}
+
+// GetVolumesByDevicePaths resolves host device paths (see GetVolumeByWWN) or NVMe NGUIDs to volumes.
+// Each identifier is looked up with a filtered query of the v1 volumes endpoint, or, for more than
+// DevicePathBulkLookupThreshold devices, the identifiers of every volume are read in a single paged query.
+// The v1 endpoint requires Unisphere 10.1 or above; if the array does not support it each device is looked up
+// through the volume iterator by WWN and then NGUID instead.
+// The result is in the order of devicePaths, and the VolumeID of a device with no volume is empty.
+func (c *Client) GetVolumesByDevicePaths(ctx context.Context, symID string, devicePaths []string) ([]DeviceVolume, error) {
+	defer c.TimeSpent("GetVolumesByDevicePaths", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	result := make([]DeviceVolume, 0, len(devicePaths))
+	for _, devicePath := range devicePaths {
+		identifier, err := deviceIdentifier(devicePath)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, DeviceVolume{DevicePath: devicePath, Identifier: identifier})
+	}
+	if len(result) == 0 {
+		return result, nil
+	}
+
+	var err error
+	if len(result) > DevicePathBulkLookupThreshold {
+		err = c.resolveDevicesBulk(ctx, symID, result)
+	} else {
+		err = c.resolveDevicesFiltered(ctx, symID, result)
+	}
+	if err == nil {
+		return result, nil
+	}
+	// Older Unisphere versions reject the endpoint or the selected fields
+	if !types.IsNotFoundError(err) && !types.IsBadRequestError(err) {
+		log.Error("GetVolumesByDevicePaths failed: " + err.Error())
+		return nil, err
+	}
+	log.Warnf("v1 volume identifier query not supported, looking up devices through the volume iterator: %s", err.Error())
+
+	for i := range result {
+		result[i].VolumeID = ""
+		volume, err := c.findVolumeByIdentifier(ctx, symID, result[i].Identifier, volumeIdentifierFields)
+		if err != nil {
+			return nil, err
+		}
+		if volume != nil {
+			result[i].VolumeID = volume.VolumeID
+		}
+	}
+	return result, nil
+}
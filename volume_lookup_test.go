@@ -0,0 +1,174 @@
+/*
+ *
+ * Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pmax
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dell/gopowermax/v2/mock"
+	types "github.com/dell/gopowermax/v2/types/v100"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeviceIdentifier(t *testing.T) {
+	const wwn = "60000970000197900046533030304131"
+	tests := []struct {
+		devicePath string
+		want       string
+		wantErr    bool
+	}{
+		{"/dev/disk/by-id/wwn-0x" + wwn, wwn, false},
+		{"/dev/disk/by-id/scsi-3" + wwn, wwn, false},
+		{"/dev/disk/by-id/dm-uuid-mpath-3" + wwn, wwn, false},
+		{"/dev/disk/by-id/nvme-eui." + wwn, wwn, false},
+		{"naa.60000970000197900046533030304131", wwn, false},
+		{"0x60000970000197900046533030304131", wwn, false},
+		{"60000970-0001-9790-0046-533030304131", wwn, false},
+		{strings.ToUpper(wwn), wwn, false},
+		{"/dev/sda", "", true},
+		{"10000000c9aaaaaa", "", true},
+	}
+	for _, tt := range tests {
+		got, err := deviceIdentifier(tt.devicePath)
+		if tt.wantErr {
+			assert.Error(t, err, tt.devicePath)
+			continue
+		}
+		assert.NoError(t, err, tt.devicePath)
+		assert.Equal(t, tt.want, got, tt.devicePath)
+	}
+}
+
+func TestGetVolumeByWWN(t *testing.T) {
+	c := newMockReplicationClient(t)
+	assert.NoError(t, mock.AddNewVolume("0A1C2", "wwn-vol", 1, mock.DefaultStorageGroup))
+	assert.NoError(t, mock.AddNewVolume("0A1C3", "other-vol", 1, mock.DefaultStorageGroup))
+
+	volume, err := c.GetVolumeByWWN(context.Background(), mock.DefaultSymmetrixID, "/dev/disk/by-id/wwn-0x6000097000019790004653303030a1c2")
+	assert.NoError(t, err)
+	assert.Equal(t, "0A1C2", volume.VolumeID)
+
+	volume, err = c.GetVolumeByWWN(context.Background(), mock.DefaultSymmetrixID, "scsi-36000097000019790004653303030A1C3")
+	assert.NoError(t, err)
+	assert.Equal(t, "0A1C3", volume.VolumeID)
+
+	_, err = c.GetVolumeByWWN(context.Background(), mock.DefaultSymmetrixID, "600009700001979000465330303FFFFF")
+	assert.ErrorContains(t, err, "no volume found")
+
+	_, err = c.GetVolumeByWWN(context.Background(), mock.DefaultSymmetrixID, "/dev/sda")
+	assert.Error(t, err)
+}
+
+func TestGetVolumesByDevicePaths(t *testing.T) {
+	c := newMockReplicationClient(t)
+	assert.NoError(t, mock.AddNewVolume("0A1D2", "path-vol", 1, mock.DefaultStorageGroup))
+	assert.NoError(t, mock.AddNewVolume("0A1D3", "path-vol-2", 1, mock.DefaultStorageGroup))
+
+	devicePaths := []string{
+		"/dev/disk/by-id/nvme-eui.6000097000019790004653303030a1d3",
+		"/dev/disk/by-id/wwn-0x6000097000019790004653303030a1d2",
+		"/dev/disk/by-id/wwn-0x600009700001979000465330303fffff",
+	}
+	// each identifier is filtered on the array, unless there are more devices than the threshold
+	defer func(threshold int) { DevicePathBulkLookupThreshold = threshold }(DevicePathBulkLookupThreshold)
+	for _, threshold := range []int{len(devicePaths), len(devicePaths) - 1} {
+		DevicePathBulkLookupThreshold = threshold
+		devices, err := c.GetVolumesByDevicePaths(context.Background(), mock.DefaultSymmetrixID, devicePaths)
+		assert.NoError(t, err)
+		assert.Len(t, devices, 3)
+		assert.Equal(t, devicePaths[0], devices[0].DevicePath)
+		assert.Equal(t, "0A1D3", devices[0].VolumeID)
+		assert.Equal(t, "0A1D2", devices[1].VolumeID)
+		assert.Equal(t, "600009700001979000465330303fffff", devices[2].Identifier)
+		assert.Empty(t, devices[2].VolumeID)
+	}
+
+	_, err := c.GetVolumesByDevicePaths(context.Background(), mock.DefaultSymmetrixID, []string{"/dev/sda"})
+	assert.Error(t, err)
+}
+
+// TestGetVolumesByDevicePathsFiltered checks that a few devices are resolved with filtered queries
+// rather than by reading every volume.
+func TestGetVolumesByDevicePathsFiltered(t *testing.T) {
+	symID := "000000000001"
+	const wwn = "60000970000197900046533030300001"
+	var filters []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if !strings.HasSuffix(r.URL.Path, XVolumeV1) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"not found"}`))
+			return
+		}
+		filter := r.URL.Query().Get("filter")
+		filters = append(filters, filter)
+		out := &types.Volumev1{}
+		if filter == "wwn EQ "+wwn {
+			out.Volumes = []types.VolumeEnhanced{{ID: "00001", WWN: strings.ToUpper(wwn)}}
+		}
+		_ = json.NewEncoder(w).Encode(out)
+	}))
+	defer server.Close()
+	c, err := NewClientWithArgs(server.URL, "", true, true, "")
+	assert.NoError(t, err)
+	c.SetAllowedArrays([]string{symID})
+
+	devices, err := c.GetVolumesByDevicePaths(context.Background(), symID, []string{"wwn-0x" + wwn})
+	assert.NoError(t, err)
+	assert.Equal(t, "00001", devices[0].VolumeID)
+	assert.Equal(t, []string{"effective_wwn EQ " + wwn, "wwn EQ " + wwn}, filters)
+}
+
+// TestGetVolumesByDevicePathsFallback uses an array without the v1 volumes endpoint, so each
+// device is looked up through the volume iterator.
+func TestGetVolumesByDevicePathsFallback(t *testing.T) {
+	symID := "000000000001"
+	const wwn = "60000970000197900046533030300001"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var out interface{}
+		switch {
+		case strings.HasSuffix(r.URL.Path, XVolume) && r.URL.Query().Get("effective_wwn") == wwn:
+			out = &types.VolumeIterator{ResultList: types.VolumeResultList{VolumeList: []types.VolumeIDList{{VolumeIDs: "00001"}}, From: 1, To: 1}, Count: 1}
+		case strings.HasSuffix(r.URL.Path, XVolume):
+			out = &types.VolumeIterator{}
+		case strings.HasSuffix(r.URL.Path, XVolume+"/00001"):
+			out = &types.Volume{VolumeID: "00001", EffectiveWWN: wwn}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"not found"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(out)
+	}))
+	defer server.Close()
+	c, err := NewClientWithArgs(server.URL, "", true, true, "")
+	assert.NoError(t, err)
+	c.SetAllowedArrays([]string{symID})
+
+	devices, err := c.GetVolumesByDevicePaths(context.Background(), symID, []string{"wwn-0x" + wwn, "nvme-eui.600009700001979000465330303fffff"})
+	assert.NoError(t, err)
+	assert.Equal(t, "00001", devices[0].VolumeID)
+	assert.Empty(t, devices[1].VolumeID)
+}
@@ -0,0 +1,315 @@
+/*
+ Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pmax
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+	log "github.com/sirupsen/logrus"
+)
+
+// RDF types accepted by VolumeQuery.RDFType. They match the prefix of the volume type, for instance RDF1+TDEV.
+const (
+	VolumeRDFTypeR1  = "RDF1"
+	VolumeRDFTypeR2  = "RDF2"
+	VolumeRDFTypeR21 = "RDF21"
+)
+
+// volumeComparison is a single numeric condition of a VolumeQuery. Op is one of >, < or =.
+type volumeComparison struct {
+	op    string
+	value float64
+}
+
+// VolumeQuery is a typed filter over the volumes of an array. It is built with NewVolumeQuery and its chained
+// setters, every condition of which must hold for a volume to match, and translated by LegacyParams for the
+// sloprovisioning volume endpoint or by V1Filters for the v1 volumes endpoint. Capacity and allocation bounds
+// are strict, as they are in Unisphere. The first invalid setter argument is kept and returned on translation.
+type VolumeQuery struct {
+	capacityGB       []volumeComparison
+	allocatedPercent []volumeComparison
+	storageGroupID   string
+	mapped           *bool
+	snapVXSource     *bool
+	snapVXTarget     *bool
+	rdfType          string
+	emulation        string
+	identifier       string
+	identifierLike   bool
+	err              error
+}
+
+// NewVolumeQuery returns a VolumeQuery that matches all volumes.
+func NewVolumeQuery() *VolumeQuery {
+	return &VolumeQuery{}
+}
+
+func (q *VolumeQuery) fail(format string, args ...interface{}) *VolumeQuery {
+	if q.err == nil {
+		q.err = fmt.Errorf(format, args...)
+	}
+	return q
+}
+
+func (q *VolumeQuery) compare(conditions *[]volumeComparison, name, op string, value float64) *VolumeQuery {
+	if value < 0 {
+		return q.fail("%s must not be negative: %g", name, value)
+	}
+	*conditions = append(*conditions, volumeComparison{op: op, value: value})
+	return q
+}
+
+// CapacityGBGreaterThan matches volumes larger than gb.
+func (q *VolumeQuery) CapacityGBGreaterThan(gb float64) *VolumeQuery {
+	return q.compare(&q.capacityGB, "capacity", ">", gb)
+}
+
+// CapacityGBLessThan matches volumes smaller than gb.
+func (q *VolumeQuery) CapacityGBLessThan(gb float64) *VolumeQuery {
+	return q.compare(&q.capacityGB, "capacity", "<", gb)
+}
+
+// CapacityGB matches volumes of exactly gb.
+func (q *VolumeQuery) CapacityGB(gb float64) *VolumeQuery {
+	return q.compare(&q.capacityGB, "capacity", "=", gb)
+}
+
+// AllocatedPercentGreaterThan matches volumes with more than percent of their capacity allocated.
+func (q *VolumeQuery) AllocatedPercentGreaterThan(percent float64) *VolumeQuery {
+	if percent > 100 {
+		return q.fail("allocated percent must not exceed 100: %g", percent)
+	}
+	return q.compare(&q.allocatedPercent, "allocated percent", ">", percent)
+}
+
+// AllocatedPercentLessThan matches volumes with less than percent of their capacity allocated.
+func (q *VolumeQuery) AllocatedPercentLessThan(percent float64) *VolumeQuery {
+	if percent > 100 {
+		return q.fail("allocated percent must not exceed 100: %g", percent)
+	}
+	return q.compare(&q.allocatedPercent, "allocated percent", "<", percent)
+}
+
+// InStorageGroup matches volumes in storageGroupID.
+func (q *VolumeQuery) InStorageGroup(storageGroupID string) *VolumeQuery {
+	if storageGroupID == "" {
+		return q.fail("storageGroupID is empty")
+	}
+	q.storageGroupID = storageGroupID
+	return q
+}
+
+// Mapped matches volumes that are mapped to a front end port, or that are not if mapped is false.
+func (q *VolumeQuery) Mapped(mapped bool) *VolumeQuery {
+	q.mapped = &mapped
+	return q
+}
+
+// SnapVXSource matches volumes that are, or if source is false are not, the source of a SnapVX snapshot.
+func (q *VolumeQuery) SnapVXSource(source bool) *VolumeQuery {
+	q.snapVXSource = &source
+	return q
+}
+
+// SnapVXTarget matches volumes that are, or if target is false are not, linked to a SnapVX snapshot.
+func (q *VolumeQuery) SnapVXTarget(target bool) *VolumeQuery {
+	q.snapVXTarget = &target
+	return q
+}
+
+// RDFType matches volumes of rdfType, one of VolumeRDFTypeR1, VolumeRDFTypeR2 or VolumeRDFTypeR21.
+func (q *VolumeQuery) RDFType(rdfType string) *VolumeQuery {
+	switch rdfType {
+	case VolumeRDFTypeR1, VolumeRDFTypeR2, VolumeRDFTypeR21:
+		q.rdfType = rdfType
+		return q
+	}
+	return q.fail("unknown RDF type %s", rdfType)
+}
+
+// Emulation matches volumes with the given emulation, for instance FBA.
+func (q *VolumeQuery) Emulation(emulation string) *VolumeQuery {
+	if emulation == "" {
+		return q.fail("emulation is empty")
+	}
+	q.emulation = emulation
+	return q
+}
+
+// IdentifierEquals matches volumes whose identifier is identifier.
+func (q *VolumeQuery) IdentifierEquals(identifier string) *VolumeQuery {
+	if identifier == "" {
+		return q.fail("identifier is empty")
+	}
+	q.identifier, q.identifierLike = identifier, false
+	return q
+}
+
+// IdentifierLike matches volumes whose identifier contains pattern.
+func (q *VolumeQuery) IdentifierLike(pattern string) *VolumeQuery {
+	if pattern == "" {
+		return q.fail("identifier pattern is empty")
+	}
+	q.identifier, q.identifierLike = pattern, true
+	return q
+}
+
+func formatVolumeQueryNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// LegacyParams returns the query parameters for GetVolumeIDListWithParams, GetVolumeIDsIteratorWithParams and
+// StreamVolumeIDs.
+func (q *VolumeQuery) LegacyParams() (map[string]string, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	params := make(map[string]string)
+	comparisons := func(key string, conditions []volumeComparison) {
+		values := make([]string, len(conditions))
+		for i, cond := range conditions {
+			values[i] = cond.op + formatVolumeQueryNumber(cond.value)
+		}
+		if len(values) > 0 {
+			params[key] = strings.Join(values, ",")
+		}
+	}
+	flag := func(key string, value *bool) {
+		if value != nil {
+			params[key] = strconv.FormatBool(*value)
+		}
+	}
+	comparisons("cap_gb", q.capacityGB)
+	comparisons("allocated_percent", q.allocatedPercent)
+	flag("mapped", q.mapped)
+	flag("snapvx_source", q.snapVXSource)
+	flag("snapvx_target", q.snapVXTarget)
+	if q.storageGroupID != "" {
+		params["storageGroupId"] = url.QueryEscape(q.storageGroupID)
+	}
+	if q.rdfType != "" {
+		params["type"] = "<like>" + url.QueryEscape(q.rdfType+"+")
+	}
+	if q.emulation != "" {
+		params["emulation"] = url.QueryEscape(q.emulation)
+	}
+	if q.identifier != "" {
+		params["volume_identifier"] = url.QueryEscape(q.identifier)
+		if q.identifierLike {
+			params["volume_identifier"] = "<like>" + params["volume_identifier"]
+		}
+	}
+	return params, nil
+}
+
+// V1Filters returns the filter expressions for the v1 volumes endpoint, each of which is sent as a filter
+// query parameter.
+func (q *VolumeQuery) V1Filters() ([]string, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	operators := map[string]string{">": "GT", "<": "LT", "=": "EQ"}
+	var filters []string
+	add := func(field, op, value string) {
+		filters = append(filters, field+" "+op+" "+value)
+	}
+	for _, cond := range q.capacityGB {
+		add("cap_gb", operators[cond.op], formatVolumeQueryNumber(cond.value))
+	}
+	for _, cond := range q.allocatedPercent {
+		add("allocated_percent", operators[cond.op], formatVolumeQueryNumber(cond.value))
+	}
+	if q.mapped != nil {
+		if *q.mapped {
+			add("num_of_front_end_paths", "GT", "0")
+		} else {
+			add("num_of_front_end_paths", "EQ", "0")
+		}
+	}
+	if q.snapVXSource != nil {
+		add("snapvx_source", "EQ", strconv.FormatBool(*q.snapVXSource))
+	}
+	if q.snapVXTarget != nil {
+		add("snapvx_target", "EQ", strconv.FormatBool(*q.snapVXTarget))
+	}
+	if q.storageGroupID != "" {
+		add("storage_groups.id", "EQ", q.storageGroupID)
+	}
+	if q.rdfType != "" {
+		add("type", "like", q.rdfType+"+")
+	}
+	if q.emulation != "" {
+		add("emulation", "EQ", q.emulation)
+	}
+	if q.identifier != "" {
+		if q.identifierLike {
+			add("identifier", "like", q.identifier)
+		} else {
+			add("identifier", "EQ", q.identifier)
+		}
+	}
+	return filters, nil
+}
+
+// GetVolumeIDListByQuery returns the ids of the volumes matching query using the sloprovisioning volume endpoint.
+func (c *Client) GetVolumeIDListByQuery(ctx context.Context, symID string, query *VolumeQuery) ([]string, error) {
+	defer c.TimeSpent("GetVolumeIDListByQuery", time.Now())
+	params, err := query.LegacyParams()
+	if err != nil {
+		return nil, err
+	}
+	return c.GetVolumeIDListWithParams(ctx, symID, params)
+}
+
+// GetVolumesByQuery returns the volumes matching query using the v1 volumes endpoint, which requires
+// Unisphere 10.1 or above. The volumes have their id, type, identifier, cap_gb, storage groups and masking view
+// count, and the fields query filters on. Results are aggregated across all pages.
+func (c *Client) GetVolumesByQuery(ctx context.Context, symID string, query *VolumeQuery) (*types.Volumev1, error) {
+	defer c.TimeSpent("GetVolumesByQuery", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	filters, err := query.V1Filters()
+	if err != nil {
+		return nil, err
+	}
+	baseURL := c.urlPrefixV1() + symID + XVolumeV1 + SelectQuery + SelectID + SelectType + SelectIdentifier +
+		SelectCapGB + SelectStorageGroupID + SelectNumberOfMaskingViews
+	// the fields filtered on are selected too, so callers can see why a volume matched
+	selected := []string{"id", "type", "identifier", "cap_gb", "storage_groups.id", "num_of_masking_views"}
+	for _, filter := range filters {
+		if field := strings.Fields(filter)[0]; !stringInSlice(field, selected) {
+			selected = append(selected, field)
+			baseURL += "," + field
+		}
+	}
+	for _, filter := range filters {
+		baseURL += "&filter=" + strings.ReplaceAll(url.QueryEscape(filter), "+", "%20")
+	}
+	baseURL += "&limit=1000&expiration_delay_secs=30"
+
+	allVolumes, err := c.getVolumesV1(ctx, baseURL)
+	if err != nil {
+		log.Error("GetVolumesByQuery failed: " + err.Error())
+		return nil, err
+	}
+	return &types.Volumev1{Volumes: allVolumes}, nil
+}
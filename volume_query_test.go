@@ -0,0 +1,172 @@
+/*
+ *
+ * Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pmax
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVolumeQueryTranslation(t *testing.T) {
+	query := NewVolumeQuery().
+		CapacityGBGreaterThan(10).
+		CapacityGBLessThan(100.5).
+		AllocatedPercentGreaterThan(80).
+		InStorageGroup("sg_1").
+		Mapped(false).
+		SnapVXSource(true).
+		SnapVXTarget(false).
+		RDFType(VolumeRDFTypeR2).
+		Emulation(Emulation).
+		IdentifierLike("csi pvc")
+
+	params, err := query.LegacyParams()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"cap_gb":            ">10,<100.5",
+		"allocated_percent": ">80",
+		"storageGroupId":    "sg_1",
+		"mapped":            "false",
+		"snapvx_source":     "true",
+		"snapvx_target":     "false",
+		"type":              "<like>RDF2%2B",
+		"emulation":         "FBA",
+		"volume_identifier": "<like>csi+pvc",
+	}, params)
+
+	filters, err := query.V1Filters()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"cap_gb GT 10",
+		"cap_gb LT 100.5",
+		"allocated_percent GT 80",
+		"num_of_front_end_paths EQ 0",
+		"snapvx_source EQ true",
+		"snapvx_target EQ false",
+		"storage_groups.id EQ sg_1",
+		"type like RDF2+",
+		"emulation EQ FBA",
+		"identifier like csi pvc",
+	}, filters)
+
+	params, err = NewVolumeQuery().CapacityGB(5).Mapped(true).IdentifierEquals("vol").LegacyParams()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"cap_gb": "=5", "mapped": "true", "volume_identifier": "vol"}, params)
+	filters, err = NewVolumeQuery().CapacityGB(5).Mapped(true).IdentifierEquals("vol").V1Filters()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cap_gb EQ 5", "num_of_front_end_paths GT 0", "identifier EQ vol"}, filters)
+
+	params, err = NewVolumeQuery().LegacyParams()
+	assert.NoError(t, err)
+	assert.Empty(t, params)
+
+	tests := []struct {
+		name  string
+		query *VolumeQuery
+		err   string
+	}{
+		{"negative capacity", NewVolumeQuery().CapacityGBGreaterThan(-1), "capacity must not be negative"},
+		{"percent", NewVolumeQuery().AllocatedPercentLessThan(101), "must not exceed 100"},
+		{"rdf type", NewVolumeQuery().RDFType("R1"), "unknown RDF type R1"},
+		{"first error wins", NewVolumeQuery().InStorageGroup("").Emulation(""), "storageGroupID is empty"},
+		{"identifier", NewVolumeQuery().IdentifierLike(""), "identifier pattern is empty"},
+	}
+	for _, tt := range tests {
+		_, err := tt.query.LegacyParams()
+		assert.ErrorContains(t, err, tt.err, tt.name)
+		_, err = tt.query.V1Filters()
+		assert.ErrorContains(t, err, tt.err, tt.name)
+	}
+}
+
+func TestGetVolumesByQuery(t *testing.T) {
+	symID := "000000000001"
+	var legacyQuery []string
+	var filters [][]string
+	var selects []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var out interface{}
+		switch {
+		case strings.HasSuffix(r.URL.Path, XVolume):
+			legacyQuery = strings.Split(r.URL.RawQuery, "&")
+			sort.Strings(legacyQuery)
+			out = &types.VolumeIterator{ID: "iter-1", Count: 1, MaxPageSize: 10, ResultList: types.VolumeResultList{
+				From: 1, To: 1, VolumeList: []types.VolumeIDList{{VolumeIDs: "00001"}},
+			}}
+		case strings.HasSuffix(r.URL.Path, XVolumeV1):
+			filters = append(filters, r.URL.Query()["filter"])
+			selects = append(selects, r.URL.Query().Get("select"))
+			if r.URL.Query().Get("resume_token") == "" {
+				out = &types.Volumev1{
+					Volumes:      []types.VolumeEnhanced{{ID: "00001"}},
+					VolumePaging: types.VolumePaging{ResumeToken: "next", TotalInstances: 2, RemainingInstances: 1},
+				}
+			} else {
+				out = &types.Volumev1{Volumes: []types.VolumeEnhanced{{ID: "00002"}}}
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"not found"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(out)
+	}))
+	defer server.Close()
+	c, err := NewClientWithArgs(server.URL, "", true, true, "")
+	assert.NoError(t, err)
+	c.SetAllowedArrays([]string{symID})
+
+	query := NewVolumeQuery().CapacityGBGreaterThan(10).CapacityGBLessThan(100).IdentifierLike("pvc-1").RDFType(VolumeRDFTypeR1)
+	ids, err := c.GetVolumeIDListByQuery(context.Background(), symID, query)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"00001"}, ids)
+	assert.Equal(t, []string{"cap_gb<100", "cap_gb>10", "type=<like>RDF1%2B", "volume_identifier=<like>pvc-1"}, legacyQuery)
+
+	volumes, err := c.GetVolumesByQuery(context.Background(), symID, query)
+	assert.NoError(t, err)
+	assert.Len(t, volumes.Volumes, 2)
+	assert.Len(t, filters, 2)
+	assert.Equal(t, []string{"cap_gb GT 10", "cap_gb LT 100", "type like RDF1+", "identifier like pvc-1"}, filters[0])
+	assert.Equal(t, filters[0], filters[1])
+	assert.Equal(t, "id,type,identifier,cap_gb,storage_groups.id,num_of_masking_views", selects[0])
+
+	// the fields filtered on are selected
+	selects = nil
+	_, err = c.GetVolumesByQuery(context.Background(), symID, NewVolumeQuery().AllocatedPercentGreaterThan(50).Mapped(true).
+		SnapVXSource(true).SnapVXTarget(false).Emulation(Emulation).CapacityGB(5))
+	assert.NoError(t, err)
+	assert.Equal(t, "id,type,identifier,cap_gb,storage_groups.id,num_of_masking_views,allocated_percent,"+
+		"num_of_front_end_paths,snapvx_source,snapvx_target,emulation", selects[0])
+
+	_, err = c.GetVolumeIDListByQuery(context.Background(), symID, NewVolumeQuery().RDFType("bad"))
+	assert.ErrorContains(t, err, "unknown RDF type")
+	_, err = c.GetVolumesByQuery(context.Background(), symID, NewVolumeQuery().RDFType("bad"))
+	assert.ErrorContains(t, err, "unknown RDF type")
+	_, err = c.GetVolumesByQuery(context.Background(), "000000000002", query)
+	assert.Error(t, err)
+}
@@ -475,6 +475,59 @@ func (c *Client) GetSnapshotGenerationInfo(ctx context.Context, symID, volumeID,
 	return volumeSnapshotGeneration, nil
 }
 
+// CreateVolumeFromSnapshot creates a volume named volumeName in storageGroupID directly from a generation of
+// the snapshot snapID of sourceVolumeID, using the v1 create volume API. The new volume has the size of the
+// source volume. This replaces creating a target volume and linking and unlinking the snapshot to it.
+func (c *Client) CreateVolumeFromSnapshot(ctx context.Context, symID, sourceVolumeID, snapID string, generation int64, storageGroupID, volumeName string, opts ...http.Header) (*types.Volume, error) {
+	defer c.TimeSpent("CreateVolumeFromSnapshot", time.Now())
+	if _, err := c.IsAllowedArray(symID); err != nil {
+		return nil, err
+	}
+	if len(volumeName) > MaxVolIdentifierLength {
+		return nil, fmt.Errorf("Length of volumeName exceeds max limit")
+	}
+	snapshot, err := c.GetSnapshotGenerationInfo(ctx, symID, sourceVolumeID, snapID, generation)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot.VolumeSnapshotSource.SnapID == 0 {
+		return nil, fmt.Errorf("snapshot %s generation %d of volume %s not found", snapID, generation, sourceVolumeID)
+	}
+
+	param := types.VolumeRequestParam{
+		RequestID: volumeName,
+		CreateNew: &types.CreateVolumeParam{
+			CreateNewFromSnapshot: &types.CreateNewFromSnapshot{
+				Snapshot: types.SnapshotRequestParam{ID: strconv.FormatInt(snapshot.VolumeSnapshotSource.SnapID, 10)},
+			},
+		},
+		Actions: &types.VolumeRequestParamActions{
+			ManageIdentifier: &types.ManageIdentifierAction{
+				Action:     "Set",
+				Identifier: volumeName,
+			},
+		},
+	}
+	if storageGroupID != "" {
+		param.Actions.ManageVolumeStorageGroup = &types.ManageVolumeStorageGroupAction{
+			Action:       "ADD",
+			StorageGroup: types.VolumeStorageGroupParam{ID: storageGroupID},
+		}
+	}
+	req := types.CreateVolumesRequest{
+		Volumes:         []types.VolumeRequestParam{param},
+		ExecutionOption: types.ExecutionOptionSynchronous,
+	}
+	resp, err := c.CreateVolume(ctx, symID, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Results.Result) == 0 || resp.Results.Result[0].Volume == nil || resp.Results.Result[0].Volume.ID == "" {
+		return nil, fmt.Errorf("no volume returned creating %s from snapshot %s", volumeName, snapID)
+	}
+	return c.GetVolumeByID(ctx, symID, resp.Results.Result[0].Volume.ID)
+}
+
 // GetReplicationCapabilities returns details about SnapVX and SRDF
 // execution capabilities on the Symmetrix array
 func (c *Client) GetReplicationCapabilities(ctx context.Context) (*types.SymReplicationCapabilities, error) {
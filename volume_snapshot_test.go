@@ -0,0 +1,47 @@
+/*
+ *
+ * Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pmax
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dell/gopowermax/v2/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateVolumeFromSnapshot(t *testing.T) {
+	c := newMockReplicationClient(t)
+	assert.NoError(t, mock.AddNewVolume("0C001", "restore-source", 547, mock.DefaultStorageGroup))
+	mock.AddNewSnapshot("0C001", "restore-snap")
+
+	volume, err := c.CreateVolumeFromSnapshot(context.Background(), mock.DefaultSymmetrixID, "0C001", "restore-snap", 0, mock.DefaultStorageGroup, "restored-vol")
+	assert.NoError(t, err)
+	assert.Equal(t, "restored-vol", volume.VolumeIdentifier)
+	assert.Equal(t, 547, volume.CapacityCYL)
+	assert.Contains(t, volume.StorageGroupIDList, mock.DefaultStorageGroup)
+
+	_, err = c.CreateVolumeFromSnapshot(context.Background(), mock.DefaultSymmetrixID, "0C001", "no-such-snap", 0, mock.DefaultStorageGroup, "restored-vol-2")
+	assert.ErrorContains(t, err, "not found")
+
+	mock.InducedErrors.CreateVolumeError = true
+	_, err = c.CreateVolumeFromSnapshot(context.Background(), mock.DefaultSymmetrixID, "0C001", "restore-snap", 0, mock.DefaultStorageGroup, "restored-vol-3")
+	assert.Error(t, err)
+	mock.InducedErrors.CreateVolumeError = false
+}
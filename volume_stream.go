@@ -0,0 +1,166 @@
+/*
+ Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+      http://www.apache.org/licenses/LICENSE-2.0
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package pmax
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"time"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+	log "github.com/sirupsen/logrus"
+)
+
+// volumeIDPage is one page of volume IDs read from a volume iterator, or the error that ended the iteration.
+type volumeIDPage struct {
+	ids []string
+	err error
+}
+
+// StreamVolumeIDs returns a sequence of the IDs of the volumes matching queryParams, which are interpreted as
+// by GetVolumeIDsIteratorWithParams. The next page is fetched while the caller consumes the current one.
+// An error ends the sequence. The server side iterator is deleted when the sequence ends for any reason,
+// including the caller breaking out of the loop or ctx being cancelled.
+func (c *Client) StreamVolumeIDs(ctx context.Context, symID string, queryParams map[string]string) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		c.streamVolumeIDPages(ctx, symID, queryParams, func(page volumeIDPage) bool {
+			if page.err != nil {
+				yield("", page.err)
+				return false
+			}
+			for _, id := range page.ids {
+				if !yield(id, nil) {
+					return false
+				}
+			}
+			return true
+		})
+	}
+}
+
+// StreamVolumes returns a sequence of the volumes matching queryParams, as StreamVolumeIDs does, fetching each
+// page of volumes with at most concurrency requests in flight (DefaultRequestConcurrency if concurrency is not
+// positive). Volumes are yielded in iterator order. A volume that cannot be fetched, for instance because it was
+// deleted after the iterator was created, is yielded as a nil volume with its error and the sequence continues;
+// an error reading the iterator itself ends the sequence.
+func (c *Client) StreamVolumes(ctx context.Context, symID string, queryParams map[string]string, concurrency int) iter.Seq2[*types.Volume, error] {
+	if concurrency <= 0 {
+		concurrency = DefaultRequestConcurrency
+	}
+	return func(yield func(*types.Volume, error) bool) {
+		c.streamVolumeIDPages(ctx, symID, queryParams, func(page volumeIDPage) bool {
+			if page.err != nil {
+				yield(nil, page.err)
+				return false
+			}
+			volumes := make([]*types.Volume, len(page.ids))
+			errs := make([]error, len(page.ids))
+			if err := runBounded(ctx, concurrency, len(page.ids), func(i int) {
+				volumes[i], errs[i] = c.GetVolumeByID(ctx, symID, page.ids[i])
+				if errs[i] != nil {
+					errs[i] = fmt.Errorf("volume %s: %w", page.ids[i], errs[i])
+				}
+			}); err != nil {
+				yield(nil, err)
+				return false
+			}
+			for i := range volumes {
+				if !yield(volumes[i], errs[i]) {
+					return false
+				}
+			}
+			return true
+		})
+	}
+}
+
+// streamVolumeIDPages creates a volume iterator and passes its pages to consume in order until the pages run out,
+// an error page has been consumed or consume returns false. A background goroutine reads one page ahead of
+// consume. The iterator is deleted once that goroutine has stopped.
+func (c *Client) streamVolumeIDPages(ctx context.Context, symID string, queryParams map[string]string, consume func(volumeIDPage) bool) {
+	defer c.TimeSpent("StreamVolumeIDs", time.Now())
+	it, err := c.GetVolumeIDsIteratorWithParams(ctx, symID, queryParams)
+	if err != nil {
+		consume(volumeIDPage{err: err})
+		return
+	}
+
+	pageCtx, cancel := context.WithCancel(ctx)
+	pages := make(chan volumeIDPage)
+	done := make(chan struct{})
+	var stopErr error
+	defer func() {
+		cancel()
+		<-done
+		c.deleteStreamedVolumeIterator(ctx, it)
+	}()
+
+	go func() {
+		defer close(done)
+		defer close(pages)
+		send := func(page volumeIDPage) bool {
+			select {
+			case pages <- page:
+				return page.err == nil
+			case <-pageCtx.Done():
+				stopErr = pageCtx.Err()
+				return false
+			}
+		}
+		first := make([]string, len(it.ResultList.VolumeList))
+		for i := range it.ResultList.VolumeList {
+			first[i] = it.ResultList.VolumeList[i].VolumeIDs
+		}
+		if !send(volumeIDPage{ids: first}) {
+			return
+		}
+		for from := it.ResultList.To + 1; from <= it.Count; {
+			ids, err := c.GetVolumeIDsIteratorPage(pageCtx, it, from, 0)
+			if err == nil && len(ids) == 0 {
+				err = fmt.Errorf("volume iterator %s returned no ids from %d of %d", it.ID, from, it.Count)
+			}
+			if !send(volumeIDPage{ids: ids, err: err}) {
+				return
+			}
+			from += len(ids)
+		}
+	}()
+
+	for page := range pages {
+		if err := ctx.Err(); err != nil {
+			consume(volumeIDPage{err: err})
+			return
+		}
+		if !consume(page) || page.err != nil {
+			return
+		}
+	}
+	// The goroutine only stops without an error page when ctx was cancelled before the last page was sent.
+	if stopErr != nil {
+		consume(volumeIDPage{err: stopErr})
+	}
+}
+
+// deleteStreamedVolumeIterator deletes it even if ctx has been cancelled, so that an abandoned stream does not
+// leave the iterator behind on the server until it expires.
+func (c *Client) deleteStreamedVolumeIterator(ctx context.Context, it *types.VolumeIterator) {
+	if it.ID == "" {
+		return
+	}
+	if err := c.DeleteVolumeIDsIterator(context.WithoutCancel(ctx), it); err != nil {
+		log.Warnf("failed to delete volume iterator %s: %s", it.ID, err.Error())
+	}
+}
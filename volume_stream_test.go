@@ -0,0 +1,190 @@
+/*
+ *
+ * Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package pmax
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	types "github.com/dell/gopowermax/v2/types/v100"
+	"github.com/stretchr/testify/assert"
+)
+
+// volumeStreamServer serves an iterator over volumes 00001 to 00005 in pages of two. The page starting at
+// failFrom fails and volume 00004 cannot be fetched. Deletes of the iterator are counted.
+type volumeStreamServer struct {
+	*httptest.Server
+	failFrom int
+	deletes  atomic.Int32
+}
+
+func newVolumeStreamServer(t *testing.T, failFrom int) (*volumeStreamServer, Pmax) {
+	ids := []string{"00001", "00002", "00003", "00004", "00005"}
+	page := func(from, to int) types.VolumeResultList {
+		result := types.VolumeResultList{From: from, To: to}
+		for _, id := range ids[from-1 : to] {
+			result.VolumeList = append(result.VolumeList, types.VolumeIDList{VolumeIDs: id})
+		}
+		return result
+	}
+	s := &volumeStreamServer{failFrom: failFrom}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := r.URL.Path
+		var out interface{}
+		switch {
+		case r.Method == http.MethodDelete && strings.HasSuffix(path, IteratorX+"iter-1"):
+			s.deletes.Add(1)
+			return
+		case strings.HasSuffix(path, IteratorX+"iter-1"+XPage):
+			from, _ := strconv.Atoi(r.URL.Query().Get("from"))
+			to, _ := strconv.Atoi(r.URL.Query().Get("to"))
+			if from == s.failFrom {
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte(`{"message":"page failed"}`))
+				return
+			}
+			out = page(from, to)
+		case strings.HasSuffix(path, XVolume):
+			out = &types.VolumeIterator{ID: "iter-1", Count: len(ids), MaxPageSize: 2, ResultList: page(1, 2)}
+		case strings.Contains(path, XVolume+"/") && !strings.HasSuffix(path, "/00004"):
+			out = &types.Volume{VolumeID: path[strings.LastIndex(path, "/")+1:]}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"not found"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(out)
+	}))
+	t.Cleanup(s.Close)
+	c, err := NewClientWithArgs(s.URL, "", true, true, "")
+	assert.NoError(t, err)
+	c.SetAllowedArrays([]string{"000000000001"})
+	return s, c
+}
+
+func TestStreamVolumeIDs(t *testing.T) {
+	symID := "000000000001"
+	server, c := newVolumeStreamServer(t, 0)
+
+	var ids []string
+	for id, err := range c.StreamVolumeIDs(context.Background(), symID, nil) {
+		assert.NoError(t, err)
+		ids = append(ids, id)
+	}
+	assert.Equal(t, []string{"00001", "00002", "00003", "00004", "00005"}, ids)
+	assert.Equal(t, int32(1), server.deletes.Load())
+
+	// breaking out of the loop still deletes the iterator
+	ids = nil
+	for id := range c.StreamVolumeIDs(context.Background(), symID, nil) {
+		ids = append(ids, id)
+		if len(ids) == 3 {
+			break
+		}
+	}
+	assert.Equal(t, []string{"00001", "00002", "00003"}, ids)
+	assert.Equal(t, int32(2), server.deletes.Load())
+
+	// a failed page ends the sequence with its error
+	server.failFrom = 3
+	ids = nil
+	var errs []error
+	for id, err := range c.StreamVolumeIDs(context.Background(), symID, nil) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ids = append(ids, id)
+	}
+	assert.Equal(t, []string{"00001", "00002"}, ids)
+	assert.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "page failed")
+	assert.Equal(t, int32(3), server.deletes.Load())
+
+	// cancelling the context ends the sequence with the context error
+	server.failFrom = 0
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errs = nil
+	for id, err := range c.StreamVolumeIDs(ctx, symID, nil) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if id == "00002" {
+			cancel()
+		}
+	}
+	assert.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], context.Canceled)
+	assert.Equal(t, int32(4), server.deletes.Load())
+
+	// the iterator is not created for an array that is not allowed
+	for _, err := range c.StreamVolumeIDs(context.Background(), "000000000002", nil) {
+		assert.Error(t, err)
+	}
+	assert.Equal(t, int32(4), server.deletes.Load())
+}
+
+func TestStreamVolumes(t *testing.T) {
+	symID := "000000000001"
+	server, c := newVolumeStreamServer(t, 0)
+
+	var ids []string
+	var errs []error
+	for vol, err := range c.StreamVolumes(context.Background(), symID, nil, 0) {
+		if err != nil {
+			assert.Nil(t, vol)
+			errs = append(errs, err)
+			continue
+		}
+		ids = append(ids, vol.VolumeID)
+	}
+	assert.Equal(t, []string{"00001", "00002", "00003", "00005"}, ids)
+	assert.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "volume 00004")
+	// the error of the volume request is wrapped, so a deleted volume can be told apart
+	var apiErr *types.Error
+	if assert.ErrorAs(t, errs[0], &apiErr) {
+		assert.Equal(t, http.StatusNotFound, apiErr.HTTPStatusCode)
+	}
+	assert.Equal(t, int32(1), server.deletes.Load())
+
+	server.failFrom = 5
+	ids = nil
+	errs = nil
+	for vol, err := range c.StreamVolumes(context.Background(), symID, nil, 1) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ids = append(ids, vol.VolumeID)
+	}
+	assert.Equal(t, []string{"00001", "00002", "00003"}, ids)
+	assert.Len(t, errs, 2)
+	assert.ErrorContains(t, errs[1], "page failed")
+	assert.Equal(t, int32(2), server.deletes.Load())
+}